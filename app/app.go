@@ -33,10 +33,20 @@ func New(databaseOptions protocols.DBConnectOptions) {
 		DatabaseEngine = db
 	}
 
-	DictionaryRepository = *repositories.NewDictionaryRepository(DatabaseEngine)
+	DictionaryRepository = *repositories.NewDictionaryRepository(DatabaseEngine, databaseOptions.Adapter)
 
 	DictionaryController = controllers.NewDictionaryController(
 		&DictionaryRepository,
 		NilValidator,
 	)
 }
+
+// NewWithBackend - wires the controllers to a dictionary that is not kept in a database (i.e memory.Dictionary).
+func NewWithBackend(backend protocols.DictionaryBackend) {
+	DatabaseEngine = nil
+
+	DictionaryController = controllers.NewDictionaryController(
+		backend,
+		NilValidator,
+	)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/oleoneto/redic/app/domain/protocols"
 	"github.com/oleoneto/redic/app/domain/types"
 	"github.com/oleoneto/redic/app/pkg/helpers"
+	"github.com/oleoneto/redic/app/pkg/pagination"
 )
 
 type SearchMode int
@@ -34,15 +35,39 @@ type DictionarySearch struct {
 type DictionaryController struct {
 	repository protocols.DictionaryBackend
 	validate   func(any) map[string][]string
+	ranking    *types.Ranking
+	secret     []byte
 }
 
 func NewDictionaryController(repository protocols.DictionaryBackend, validatorFunc func(any) map[string][]string) DictionaryController {
 	return DictionaryController{
 		repository: repository,
 		validate:   validatorFunc,
+		secret:     pagination.Secret(),
 	}
 }
 
+// Ranks searches by the given weights instead of types.DefaultRanking.
+func (ctr *DictionaryController) SetRanking(ranking types.Ranking) error {
+	if err := ranking.Check(); err != nil {
+		return err
+	}
+
+	ctr.ranking = &ranking
+	return nil
+}
+
+// Signs search cursors with the given secret instead of one of its own, so cursors
+// outlive the process that handed them out.
+func (ctr *DictionaryController) SetCursorSecret(secret []byte) error {
+	if len(secret) == 0 {
+		return fmt.Errorf("cursor secret is empty")
+	}
+
+	ctr.secret = secret
+	return nil
+}
+
 // Append to or create words + definitions to the dictionary
 func (ctr *DictionaryController) CreateWords(ctx context.Context, data []types.NewWordInput) error {
 	if errs := ctr.validate(data); len(errs) != 0 {
@@ -54,6 +79,24 @@ func (ctr *DictionaryController) CreateWords(ctx context.Context, data []types.N
 	return err
 }
 
+// Append to or create synsets + their members to the dictionary
+func (ctr *DictionaryController) CreateSynsets(ctx context.Context, data []types.NewSynsetInput) error {
+	if errs := ctr.validate(data); len(errs) != 0 {
+		return fmt.Errorf(`invalid data for %v`, helpers.GetCurrentFuncName())
+	}
+
+	return ctr.repository.NewSynsets(ctx, data)
+}
+
+// Append typed edges between synsets to the dictionary
+func (ctr *DictionaryController) CreateRelations(ctx context.Context, data []types.Relation) error {
+	if errs := ctr.validate(data); len(errs) != 0 {
+		return fmt.Errorf(`invalid data for %v`, helpers.GetCurrentFuncName())
+	}
+
+	return ctr.repository.NewRelations(ctx, data)
+}
+
 // Given a dictionary entry, search for its corresponding definitions.
 //
 // Example:
@@ -66,6 +109,10 @@ func (ctr *DictionaryController) GetDefinition(ctx context.Context, data types.G
 		return types.WordDefinitions{}, fmt.Errorf(`invalid data for %v`, helpers.GetCurrentFuncName())
 	}
 
+	if err := normalizeLanguages(&data.Language); err != nil {
+		return types.WordDefinitions{}, err
+	}
+
 	res, err := ctr.repository.GetWordExplanation(ctx, data)
 	if err != nil {
 		return res, err
@@ -80,16 +127,108 @@ func (ctr *DictionaryController) FindMatchingWords(ctx context.Context, data typ
 		return types.WordMatches{}, fmt.Errorf(`invalid data for %v`, helpers.GetCurrentFuncName())
 	}
 
-	fmt.Println(helpers.GetCurrentFuncName(), data)
+	if err := normalizeLanguages(&data.Language, &data.TargetLanguage); err != nil {
+		return types.WordMatches{}, err
+	}
+
+	if data.Ranking == nil {
+		data.Ranking = ctr.ranking
+	}
+
+	if data.Limit < 0 || data.Limit > types.MaxLimit {
+		return types.WordMatches{}, fmt.Errorf("%w: limit %d is not between 1 and %d", types.ErrInvalidPage, data.Limit, types.MaxLimit)
+	}
+
+	/* Cursors only page through the search they came from */
+	scope := fmt.Sprint(data.Tokens, data.PartOfSpeech, data.IncludeExplicit, data.Language, data.TargetLanguage, data.Category, data.Weights())
+
+	if data.Cursor != "" {
+		after, err := pagination.Decode(ctr.secret, scope, data.Cursor)
+		if err != nil {
+			return types.WordMatches{}, err
+		}
+
+		data.After = &after
+	}
 
 	res, err := ctr.repository.SearchWords(ctx, data)
 	if err != nil {
 		return res, err
 	}
 
+	if res.Next != nil {
+		res.NextCursor = pagination.Encode(ctr.secret, scope, *res.Next)
+	}
+
 	return res, nil
 }
 
-func (ctr *DictionaryController) IndexWords(ctx context.Context) error {
-	return ctr.repository.IndexWords(ctx)
+// Given a dictionary entry, search for words linked to it through a WordNet relation.
+//
+// Example:
+//
+//	`dog` (noun), hypernym:
+//	canine, domestic animal
+func (ctr *DictionaryController) GetRelatedWords(ctx context.Context, data types.GetRelatedWordsInput) (types.RelatedWords, error) {
+	if errs := ctr.validate(data); len(errs) != 0 {
+		return types.RelatedWords{}, fmt.Errorf(`invalid data for %v`, helpers.GetCurrentFuncName())
+	}
+
+	return ctr.repository.GetRelatedWords(ctx, data)
+}
+
+// Reads back the dictionary as flat glossary rows, one synset at a time.
+func (ctr *DictionaryController) ExportGlossary(ctx context.Context, emit func(types.GlossaryEntry) error) error {
+	return ctr.repository.ExportSynsets(ctx, func(synset types.NewSynsetInput) error {
+		for _, row := range synset.Glossary() {
+			if err := emit(row); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Returns what was last ingested from the named file.
+func (ctr *DictionaryController) GetSource(ctx context.Context, name string) (types.Source, error) {
+	return ctr.repository.GetSource(ctx, name)
+}
+
+// Returns the checksum of every synset last ingested from the named file, by synset id.
+func (ctr *DictionaryController) GetSourceChecksums(ctx context.Context, name string) (map[string]string, error) {
+	return ctr.repository.GetSourceChecksums(ctx, name)
+}
+
+// Deletes the synsets a file no longer contains and records its new hash.
+func (ctr *DictionaryController) RecordSource(ctx context.Context, update types.SourceUpdate) error {
+	return ctr.repository.RecordSource(ctx, update)
+}
+
+// Rebuilds or optimizes the full-text index.
+func (ctr *DictionaryController) IndexWords(ctx context.Context, maintenance types.IndexMaintenance) error {
+	return ctr.repository.IndexWords(ctx, maintenance)
+}
+
+// Stops writes from updating the full-text index until the function returned rebuilds it.
+func (ctr *DictionaryController) SuspendIndexing(ctx context.Context) (func(context.Context) error, error) {
+	return ctr.repository.SuspendIndexing(ctx)
+}
+
+// normalizeLanguages - canonicalizes the given BCP-47 tags in place. Empty tags are left as is.
+func normalizeLanguages(tags ...*string) error {
+	for _, tag := range tags {
+		if *tag == "" {
+			continue
+		}
+
+		normalized, err := types.NormalizeLanguage(*tag)
+		if err != nil {
+			return err
+		}
+
+		*tag = normalized
+	}
+
+	return nil
 }
@@ -7,10 +7,17 @@ import (
 )
 
 type DictionaryBackend interface {
-	IndexWords(context.Context) error
+	IndexWords(context.Context, types.IndexMaintenance) error
+	SuspendIndexing(context.Context) (func(context.Context) error, error)
 	NewWords(context.Context, []types.NewWordInput) error
+	NewSynsets(context.Context, []types.NewSynsetInput) error
+	NewRelations(context.Context, []types.Relation) error
 	// AddWordDefinitions(context.Context, types.UpdateDefinitionInput) (types.Definitions, error)
 	GetWordExplanation(context.Context, types.GetWordDefinitionsInput) (types.WordDefinitions, error)
 	SearchWords(context.Context, types.GetDescribedWordsInput) (types.WordMatches, error)
-	// GetRelatedWords(context.Context, types.GetRelatedWordsInput) (types.RelatedWords, error)
+	GetRelatedWords(context.Context, types.GetRelatedWordsInput) (types.RelatedWords, error)
+	ExportSynsets(context.Context, func(types.NewSynsetInput) error) error
+	GetSource(context.Context, string) (types.Source, error)
+	GetSourceChecksums(context.Context, string) (map[string]string, error)
+	RecordSource(context.Context, types.SourceUpdate) error
 }
@@ -2,6 +2,7 @@ package protocols
 
 import (
 	"context"
+	"io"
 	"io/fs"
 
 	"github.com/oleoneto/redic/app/domain/types"
@@ -10,6 +11,7 @@ import (
 type (
 	LoaderFunc  func(string) ([]fs.DirEntry, error)
 	ReaderFunc  func(string) ([]byte, error)
+	OpenerFunc  func(string) (io.ReadCloser, error)
 	ParserFunc  func([]byte) types.DictEntry
 	CaptureFunc func(*types.ParsedFile) error
 )
@@ -18,4 +20,5 @@ type FileParserProtocol interface {
 	LoadFiles(context.Context, string) []fs.DirEntry
 	ParseFile(context.Context, string, fs.DirEntry) (*types.ParsedFile, error)
 	ParseFiles(context.Context, string, []fs.DirEntry, CaptureFunc) ([]types.ParsedFile, error)
+	StreamFile(context.Context, string, fs.DirEntry) (<-chan types.DictEntry, <-chan error)
 }
@@ -1,6 +1,15 @@
 package types
 
-import "encoding/json"
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefinitionSeparator joins the glosses of a synset into a single explanation.
+const DefinitionSeparator = "; "
 
 type PartOfSpeech string
 
@@ -10,10 +19,26 @@ type (
 		PartOfSpeech string // i.e a
 		Definition   string // i.e comming into existence
 		Explicit     bool
+		Language     string // i.e en
+		Synset       string // i.e 00003552-s
 	}
 
 	NewWordsOutput struct{}
 
+	NewSynsetInput struct {
+		Id           string    // i.e 00003552-s
+		ILI          string    // i.e i10
+		PartOfSpeech string    // i.e s
+		Definitions  []string  // i.e coming into existence
+		Examples     []Example // i.e an emergent republic
+		Members      []string  // i.e emergent, emerging
+		Relations    []Relation
+		Explicit     bool
+		Language     string // i.e en
+		Source       string // i.e noun.animal.yaml
+		Lexfile      string // i.e noun.animal
+	}
+
 	UpdateDefinitionInput struct {
 		Word         string
 		PartOfSpeech PartOfSpeech
@@ -26,12 +51,23 @@ type (
 		Word         string       `json:"word"`
 		PartOfSpeech PartOfSpeech `json:"part_of_speech"`
 		Verbatim     bool         `json:"verbatim"`
+		Language     string       `json:"language"` // i.e pt; every language when empty
 	}
 
 	Definition struct {
+		SynsetId     string       `json:"synset_id"`
+		ILI          string       `json:"ili,omitempty"`
 		PartOfSpeech PartOfSpeech `json:"part_of_speech"`
 		Definition   string       `json:"text"`
+		Examples     []Example    `json:"examples,omitempty"`
 		Explicit     bool         `json:"explicit,omitempty"`
+		Language     string       `json:"language"`
+
+		// The base form that was defined, when the word was an inflection of it (i.e geese → goose).
+		Lemma string `json:"lemma,omitempty"`
+
+		// The lexicographer file of the synset (i.e noun.animal).
+		Lexfile string `json:"lexfile,omitempty"`
 	}
 
 	WordDefinitions struct {
@@ -40,24 +76,73 @@ type (
 	}
 
 	GetDescribedWordsInput struct {
-		Cursor          string       `json:"cursor_id"`
+		// The description, in the query syntax of the search package (i.e `"sea lion" -walrus`).
 		Tokens          string       `json:"description"`
 		PartOfSpeech    PartOfSpeech `json:"part_of_speech"`
 		IncludeExplicit bool         `json:"include_explicit"`
+
+		// Language of the description (i.e pt); every language when empty.
+		Language string `json:"language"`
+
+		// When set, matches are translated through their interlingual index (ILI)
+		// into words of this language (i.e en).
+		TargetLanguage string `json:"target_language"`
+
+		// Lexicographer file matches must belong to (i.e noun.animal); every file when empty.
+		Category string `json:"category"`
+
+		// The weights matches are ranked by; DefaultRanking when nil.
+		Ranking *Ranking `json:"-"`
+
+		// How many matches to return; DefaultLimit when 0, and never more than MaxLimit.
+		Limit int `json:"limit"`
+
+		// The next_cursor of the previous page, as handed out; the first page when empty.
+		Cursor string `json:"cursor"`
+
+		// The key the page starts after, as read from the cursor.
+		After *Cursor `json:"-"`
 	}
 
 	MatchingWord struct {
 		Id           int          `json:"id"`
+		SynsetId     string       `json:"synset_id"`
 		Word         string       `json:"word"`
 		PartOfSpeech PartOfSpeech `json:"part_of_speech"`
 		Definition   string       `json:"definition"`
 		Explicit     bool         `json:"explicit,omitempty"`
+		Language     string       `json:"language"`
+		Lexfile      string       `json:"lexfile,omitempty"` // i.e noun.animal
+
+		// How the match was ranked, when the search had a description.
+		Score *Score `json:"score,omitempty"`
+
+		// Where the description matched the definition.
+		Highlights []Span `json:"highlights,omitempty"`
+
+		// The part of the definition, or else of an example, that shows best why the word matched.
+		Snippet *Passage `json:"snippet,omitempty"`
+
+		// The definition with its highlights marked up, when markup was asked for.
+		Marked string `json:"marked,omitempty"`
 	}
 
 	WordMatches struct {
-		Cursor               string         `json:"cursor_id,omitempty"`
 		ProvidedDescriptions string         `json:"query,omitempty"`
 		MatchingWords        []MatchingWord `json:"matching_words"`
+
+		// Base forms searched for alongside inflected words of the query (i.e dogs → dog).
+		Lemmas map[string][]string `json:"lemmas,omitempty"`
+
+		// The weights matches were ranked by, when the search had a description.
+		Ranking *Ranking `json:"ranking,omitempty"`
+
+		// Whether matches remain past this page, and the cursor of the page that follows.
+		HasMore    bool   `json:"has_more"`
+		NextCursor string `json:"next_cursor,omitempty"`
+
+		// The key the next page starts after, when there is one.
+		Next *Cursor `json:"-"`
 	}
 )
 
@@ -70,6 +155,77 @@ const (
 	ALL        PartOfSpeech = "*"
 )
 
+func (w WordDefinitions) String() string {
+	var b strings.Builder
+
+	var lemmas []string
+	for _, d := range w.Definitions {
+		if d.Lemma != "" && !slices.Contains(lemmas, d.Lemma) {
+			lemmas = append(lemmas, d.Lemma)
+		}
+	}
+
+	if len(lemmas) != 0 {
+		fmt.Fprintf(&b, "%s → %s\n", w.Word, strings.Join(lemmas, ", "))
+	} else {
+		fmt.Fprintf(&b, "%s\n", w.Word)
+	}
+
+	for i, d := range w.Definitions {
+		// i.e (noun.animal) rather than (noun), when the lexicographer file is known
+		category := d.PartOfSpeech.Raw()
+		if d.Lexfile != "" {
+			category = d.Lexfile
+		}
+
+		if d.Language != "" && d.Language != DefaultLanguage {
+			fmt.Fprintf(&b, "\n%d. (%s, %s) %s\n", i+1, category, d.Language, d.Definition)
+		} else {
+			fmt.Fprintf(&b, "\n%d. (%s) %s\n", i+1, category, d.Definition)
+		}
+
+		for _, e := range d.Examples {
+			fmt.Fprintf(&b, "   %s\n", e)
+		}
+	}
+
+	return b.String()
+}
+
+func (w WordMatches) String() string {
+	var b strings.Builder
+
+	for i, m := range w.MatchingWords {
+		category := m.PartOfSpeech.Raw()
+		if m.Lexfile != "" {
+			category = m.Lexfile
+		}
+
+		definition := m.Definition
+		if m.Marked != "" {
+			definition = m.Marked
+		}
+
+		fmt.Fprintf(&b, "%d. %s (%s) %s\n", i+1, m.Word, category, definition)
+
+		/* Snippets of definitions repeat them, but those of examples show what else matched */
+		if m.Snippet != nil && len(m.Highlights) == 0 {
+			snippet := m.Snippet.Text
+			if m.Snippet.Marked != "" {
+				snippet = m.Snippet.Marked
+			}
+
+			fmt.Fprintf(&b, "   \"%s\"\n", snippet)
+		}
+	}
+
+	if w.NextCursor != "" {
+		fmt.Fprintf(&b, "\nMore matches follow: --cursor %s\n", w.NextCursor)
+	}
+
+	return b.String()
+}
+
 func (p *PartOfSpeech) MarshalJSON() ([]byte, error) {
 	type P string
 	return json.Marshal(P(p.Raw()))
@@ -89,3 +245,55 @@ func (p PartOfSpeech) Raw() string {
 
 	return "*"
 }
+
+// Definition - returns the glosses of the synset as a single explanation.
+func (s NewSynsetInput) Definition() string {
+	return strings.Join(s.Definitions, DefinitionSeparator)
+}
+
+// LocalSynsetId - derives a stable synset id for a definition that does not come from WordNet.
+//
+// Usage:
+//
+//	LocalSynsetId("n", "a word made up on the spot") // x3f1c2a9b-n
+func LocalSynsetId(partOfSpeech, definition string) string {
+	sum := sha1.Sum([]byte(partOfSpeech + "\x00" + definition))
+	return fmt.Sprintf("x%x-%s", sum[:4], partOfSpeech)
+}
+
+// Synsets - groups flat words that share a definition into synsets.
+//
+// Words that already reference a synset keep it; every other word is assigned
+// a local synset derived from its part of speech and definition.
+func Synsets(words []NewWordInput) []NewSynsetInput {
+	var synsets = []NewSynsetInput{}
+	var index = map[string]int{}
+
+	for _, word := range words {
+		id := word.Synset
+		if id == "" {
+			id = LocalSynsetId(word.PartOfSpeech, word.Definition)
+		}
+		id = QualifiedSynsetId(word.Language, id)
+
+		key := fmt.Sprintf("%s/%t", id, word.Explicit)
+
+		i, ok := index[key]
+		if !ok {
+			i = len(synsets)
+			index[key] = i
+
+			synsets = append(synsets, NewSynsetInput{
+				Id:           id,
+				PartOfSpeech: word.PartOfSpeech,
+				Definitions:  []string{word.Definition},
+				Explicit:     word.Explicit,
+				Language:     word.Language,
+			})
+		}
+
+		synsets[i].Members = append(synsets[i].Members, word.Word)
+	}
+
+	return synsets
+}
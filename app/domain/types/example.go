@@ -0,0 +1,44 @@
+package types
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Example - a sentence illustrating a synset, optionally attributed to its author.
+//
+// In the dictionary files an example is either a plain string:
+//
+//	example:
+//	- an emergent republic
+//
+// or a quotation:
+//
+//	example:
+//	- source: Winston Churchill
+//	  text: An appeaser is one who feeds a crocodile--hoping it will eat him last
+type Example struct {
+	Text   string `yaml:"text" json:"text"`
+	Source string `yaml:"source" json:"source,omitempty"`
+}
+
+func (e *Example) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Decode(&e.Text)
+	case yaml.MappingNode:
+		type E Example
+		return node.Decode((*E)(e))
+	}
+
+	return fmt.Errorf("line %d: example must be a string or a {source, text} mapping", node.Line)
+}
+
+func (e Example) String() string {
+	if e.Source == "" {
+		return fmt.Sprintf(`"%s"`, e.Text)
+	}
+
+	return fmt.Sprintf(`"%s" - %s`, e.Text, e.Source)
+}
@@ -1,6 +1,9 @@
 package types
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 /**
 00003552-s:
@@ -47,17 +50,35 @@ import "strings"
 type DictFile map[string]DictEntry
 
 type DictEntry struct {
-	Atributes    []string `yaml:"atribute" json:"atribute,omitempty"`
-	Definitions  []string `yaml:"definition" json:"definition,omitempty"`
-	Examples     any      `yaml:"example" json:"example,omitempty"`
-	Members      []string `yaml:"members" json:"members,omitempty"`
-	PartOfSpeech string   `yaml:"partOfSpeech" json:"part_of_speech,omitempty"`
-	MeroPart     []string `yaml:"mero_part" json:"mero_part,omitempty"`
-	DomainTopic  []string `yaml:"domain_topic" json:"domain_topic,omitempty"`
-	Hypernym     []string `yaml:"hypernym" json:"hypernym,omitempty"`
-	Similar      []string `yaml:"similar" json:"similar,omitempty"`
+	Id           string    `yaml:"-" json:"id,omitempty"` // i.e 00003552-s
+	File         string    `yaml:"-" json:"-"`            // i.e adj.all.yaml
+	Definitions  []string  `yaml:"definition" json:"definition,omitempty"`
+	Examples     []Example `yaml:"example" json:"example,omitempty"`
+	Members      []string  `yaml:"members" json:"members,omitempty"`
+	PartOfSpeech string    `yaml:"partOfSpeech" json:"part_of_speech,omitempty"`
+	Explicit     bool      `yaml:"-" json:"explicit,omitempty"`
+	Language     string    `yaml:"-" json:"language,omitempty"` // i.e en
+	Lexfile      string    `yaml:"-" json:"lexfile,omitempty"`  // i.e noun.animal
+
+	// Pointers to other synsets
+	Also             []string `yaml:"also" json:"also,omitempty"`
+	Attribute        []string `yaml:"attribute" json:"attribute,omitempty"`
+	Causes           []string `yaml:"causes" json:"causes,omitempty"`
+	DomainRegion     []string `yaml:"domain_region" json:"domain_region,omitempty"`
+	DomainTopic      []string `yaml:"domain_topic" json:"domain_topic,omitempty"`
+	Entails          []string `yaml:"entails" json:"entails,omitempty"`
+	Exemplifies      []string `yaml:"exemplifies" json:"exemplifies,omitempty"`
+	Hypernym         []string `yaml:"hypernym" json:"hypernym,omitempty"`
+	InstanceHypernym []string `yaml:"instance_hypernym" json:"instance_hypernym,omitempty"`
+	MeroMember       []string `yaml:"mero_member" json:"mero_member,omitempty"`
+	MeroPart         []string `yaml:"mero_part" json:"mero_part,omitempty"`
+	MeroSubstance    []string `yaml:"mero_substance" json:"mero_substance,omitempty"`
+	Similar          []string `yaml:"similar" json:"similar,omitempty"`
 
 	Identifier string `yaml:"ili" json:"ili,omitempty"`
+
+	// Relations of the entry, or of its senses, that the dictionary could not keep
+	SkippedRelations int `yaml:"-" json:"-"`
 }
 
 func (we *DictEntry) Words() []NewWordInput {
@@ -67,19 +88,104 @@ func (we *DictEntry) Words() []NewWordInput {
 		words[i] = NewWordInput{
 			Word:         word,
 			PartOfSpeech: we.PartOfSpeech,
-			Definition:   strings.Join(we.Definitions, "|"),
+			Definition:   strings.Join(we.Definitions, DefinitionSeparator),
+			Explicit:     we.Explicit,
+			Language:     we.Language,
+			Synset:       QualifiedSynsetId(we.Language, we.Id),
 		}
 	}
 
 	return words
 }
 
+// Synset - returns the entry as a synset whose members keep their WordNet order.
+func (we *DictEntry) Synset() NewSynsetInput {
+	return NewSynsetInput{
+		Id:           QualifiedSynsetId(we.Language, we.Id),
+		Language:     we.Language,
+		ILI:          we.Identifier,
+		PartOfSpeech: we.PartOfSpeech,
+		Definitions:  we.Definitions,
+		Examples:     we.Examples,
+		Members:      we.Members,
+		Relations:    we.Relations(),
+		Explicit:     we.Explicit,
+		Lexfile:      we.Lexfile,
+	}
+}
+
+// Relations - returns every pointer from this entry to another synset of its lexicon.
+func (we *DictEntry) Relations() []Relation {
+	var relations = []Relation{}
+
+	for _, pointer := range we.pointers() {
+		for _, target := range *pointer.targets {
+			relations = append(relations, Relation{
+				Source: QualifiedSynsetId(we.Language, we.Id),
+				Target: QualifiedSynsetId(we.Language, target),
+				Type:   pointer.kind,
+			})
+		}
+	}
+
+	return relations
+}
+
+// AddRelation - appends a pointer of the given type to the entry.
+//
+// Returns false if the dictionary does not store pointers of that type
+// (i.e hyponym, which is derived by following hypernyms backwards).
+func (we *DictEntry) AddRelation(kind RelationType, target string) bool {
+	for _, pointer := range we.pointers() {
+		if pointer.kind == kind {
+			*pointer.targets = append(*pointer.targets, target)
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasRelation - whether the entry already points at the target through a pointer of the given type.
+func (we *DictEntry) HasRelation(kind RelationType, target string) bool {
+	for _, pointer := range we.pointers() {
+		if pointer.kind == kind {
+			return slices.Contains(*pointer.targets, target)
+		}
+	}
+
+	return false
+}
+
+type pointer struct {
+	kind    RelationType
+	targets *[]string
+}
+
+func (we *DictEntry) pointers() []pointer {
+	return []pointer{
+		{Also, &we.Also},
+		{Attribute, &we.Attribute},
+		{Causes, &we.Causes},
+		{DomainRegion, &we.DomainRegion},
+		{DomainTopic, &we.DomainTopic},
+		{Entails, &we.Entails},
+		{Exemplifies, &we.Exemplifies},
+		{Hypernym, &we.Hypernym},
+		{InstanceHypernym, &we.InstanceHypernym},
+		{MeroMember, &we.MeroMember},
+		{MeroPart, &we.MeroPart},
+		{MeroSubstance, &we.MeroSubstance},
+		{Similar, &we.Similar},
+	}
+}
+
 type Word struct {
 	EntryCode    string
-	PartOfSpeech string   // a
-	Word         string   // emerging
-	Definitions  []string // comming into existence
-	Examples     any      // an emergent republic
+	PartOfSpeech string    // a
+	Word         string    // emerging
+	Definitions  []string  // comming into existence
+	Examples     []Example // an emergent republic
 }
 
 type ParsedFile struct {
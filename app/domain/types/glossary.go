@@ -0,0 +1,102 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/**
+word,part_of_speech,definition,explicit,example,source,synset,language,ili,lexfile,relations
+emergent,s,coming into existence,false,an emergent republic,,00003552-s,en,i10,adj.all,similar:00003356-a
+emerging,s,coming into existence,false,,,00003552-s,en,i10,adj.all,
+*/
+
+// GlossaryEntry - a row of a flat glossary (CSV, TSV or JSONL).
+//
+// Every row names one word of a synset and at most one of its examples, so a
+// synset spans as many rows as it has members or examples, whichever is larger.
+type GlossaryEntry struct {
+	Word         string `json:"word"`           // i.e emergent
+	PartOfSpeech string `json:"part_of_speech"` // i.e s
+	Definition   string `json:"definition"`     // i.e coming into existence
+	Explicit     bool   `json:"explicit"`
+	Example      string `json:"example"`  // i.e an emergent republic
+	Source       string `json:"source"`   // i.e Winston Churchill
+	Synset       string `json:"synset"`   // i.e 00003552-s
+	Language     string `json:"language"` // i.e en
+	ILI          string `json:"ili"`      // i.e i10
+	Lexfile      string `json:"lexfile"`  // i.e adj.all
+
+	// Outgoing relations of the synset, on its first row only,
+	// i.e hypernym:02083346-n;similar:00003356-a
+	Relations string `json:"relations"`
+}
+
+// Glossary - returns the rows of a synset, in member order.
+func (s NewSynsetInput) Glossary() []GlossaryEntry {
+	var rows = []GlossaryEntry{}
+
+	if len(s.Members) == 0 {
+		return rows
+	}
+
+	for i := 0; i < max(len(s.Members), len(s.Examples)); i++ {
+		row := GlossaryEntry{
+			Word:         s.Members[min(i, len(s.Members)-1)],
+			PartOfSpeech: s.PartOfSpeech,
+			Definition:   s.Definition(),
+			Explicit:     s.Explicit,
+			Synset:       s.Id,
+			Language:     s.Language,
+			ILI:          s.ILI,
+			Lexfile:      s.Lexfile,
+		}
+
+		if i < len(s.Examples) {
+			row.Example, row.Source = s.Examples[i].Text, s.Examples[i].Source
+		}
+
+		if i == 0 {
+			row.Relations = FormatRelations(s.Relations)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// FormatRelations - the type and target of every relation, i.e hypernym:02083346-n;similar:00003356-a.
+func FormatRelations(relations []Relation) string {
+	var pairs = make([]string, len(relations))
+	for i, relation := range relations {
+		pairs[i] = string(relation.Type) + ":" + relation.Target
+	}
+	return strings.Join(pairs, ";")
+}
+
+// ParseRelations - the relations FormatRelations wrote, without their source.
+// Relation types the dictionary does not store are an error.
+func ParseRelations(text string) ([]Relation, error) {
+	var relations []Relation
+
+	for _, pair := range strings.Split(text, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		kind, target, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || target == "" {
+			return nil, fmt.Errorf("invalid relation %q (expected type:synset)", pair)
+		}
+
+		if !slices.Contains(RelationTypes, RelationType(kind)) {
+			return nil, fmt.Errorf("unknown relation type %q", kind)
+		}
+
+		relations = append(relations, Relation{Type: RelationType(kind), Target: target})
+	}
+
+	return relations, nil
+}
@@ -0,0 +1,73 @@
+package types
+
+import (
+	"html"
+	"strings"
+)
+
+// Span - where a search matched a text, as byte offsets: from Start up to, but not including, End.
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Passage - a text, along with where a search matched it.
+type Passage struct {
+	Text       string `json:"text"`
+	Highlights []Span `json:"highlights"`
+
+	// The text with its highlights marked up, when markup was asked for.
+	Marked string `json:"marked,omitempty"`
+}
+
+// Markup - how highlights are set apart in text.
+type Markup struct {
+	Open, Close string
+
+	// Applied to the text within and around highlights (i.e html.EscapeString); none when nil.
+	Escape func(string) string
+}
+
+var (
+	HTMLMarkup = Markup{Open: "<mark>", Close: "</mark>", Escape: html.EscapeString}
+	ANSIMarkup = Markup{Open: "\x1b[1;33m", Close: "\x1b[0m"}
+)
+
+// Mark - the text, with every span of it between the markup's Open and Close.
+// Spans are expected in order and apart from one another.
+func (m Markup) Mark(text string, spans []Span) string {
+	var b strings.Builder
+	var escape = m.Escape
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+
+	var last int
+	for _, span := range spans {
+		if span.Start < last || span.End > len(text) {
+			continue
+		}
+
+		b.WriteString(escape(text[last:span.Start]))
+		b.WriteString(m.Open)
+		b.WriteString(escape(text[span.Start:span.End]))
+		b.WriteString(m.Close)
+		last = span.End
+	}
+
+	b.WriteString(escape(text[last:]))
+
+	return b.String()
+}
+
+// Mark - marks up the highlights of the definition and snippet of every match.
+func (w *WordMatches) Mark(m Markup) {
+	for i := range w.MatchingWords {
+		match := &w.MatchingWords[i]
+		match.Marked = m.Mark(match.Definition, match.Highlights)
+
+		if match.Snippet != nil {
+			match.Snippet.Marked = m.Mark(match.Snippet.Text, match.Snippet.Highlights)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage - the language of lexicons that do not declare one, like the bundled English WordNet.
+const DefaultLanguage = "en"
+
+// ErrInvalidLanguage - the language asked for is not a BCP-47 tag.
+var ErrInvalidLanguage = errors.New("invalid language tag")
+
+// NormalizeLanguage - validates a BCP-47 language tag and returns its canonical form.
+//
+// Usage:
+//
+//	NormalizeLanguage("pt-br") // pt-BR
+//	NormalizeLanguage("")      // en
+func NormalizeLanguage(tag string) (string, error) {
+	if strings.TrimSpace(tag) == "" {
+		return DefaultLanguage, nil
+	}
+
+	t, err := language.Parse(tag)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrInvalidLanguage, tag, err)
+	}
+
+	return t.String(), nil
+}
+
+// QualifiedSynsetId - namespaces a synset id with the language of its lexicon.
+//
+// Wordnets for other languages reuse the offsets of the English WordNet, so their synsets
+// are told apart by language. English synsets keep their plain id.
+//
+// Usage:
+//
+//	QualifiedSynsetId("en", "00003552-s") // 00003552-s
+//	QualifiedSynsetId("pt", "00003552-s") // pt:00003552-s
+func QualifiedSynsetId(lang, id string) string {
+	if lang == "" || lang == DefaultLanguage || id == "" || strings.HasPrefix(id, lang+":") {
+		return id
+	}
+
+	return lang + ":" + id
+}
+
+// MatchesLanguage - whether the language of a lexicon is the given tag, or a more or less
+// specific form of it, so pt finds pt-BR lexicons and pt-PT finds pt ones.
+func MatchesLanguage(lexicon, tag string) bool {
+	return lexicon == tag || strings.HasPrefix(lexicon, tag+"-") || strings.HasPrefix(tag, lexicon+"-")
+}
+
+// IsEnglish - whether a tag is English, or left out, as searches without a language are.
+// Morphology rules only cover English, so only its words are lemmatized.
+func IsEnglish(tag string) bool {
+	return tag == "" || MatchesLanguage(tag, DefaultLanguage)
+}
+
+// LexiconLanguage - the language a synset is stored under, English unless told otherwise.
+func LexiconLanguage(tag string) string {
+	if tag == "" {
+		return DefaultLanguage
+	}
+	return tag
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"path"
+	"strings"
+)
+
+// Lexnames - the lexicographer files of WordNet, by number (i.e 05 is noun.animal).
+// Each names a broad semantic category, or supersense, of the synsets it holds.
+var Lexnames = []string{
+	"adj.all", "adj.pert", "adv.all", "noun.Tops", "noun.act", "noun.animal", "noun.artifact",
+	"noun.attribute", "noun.body", "noun.cognition", "noun.communication", "noun.event",
+	"noun.feeling", "noun.food", "noun.group", "noun.location", "noun.motive", "noun.object",
+	"noun.person", "noun.phenomenon", "noun.plant", "noun.possession", "noun.process",
+	"noun.quantity", "noun.relation", "noun.shape", "noun.state", "noun.substance", "noun.time",
+	"verb.body", "verb.change", "verb.cognition", "verb.communication", "verb.competition",
+	"verb.consumption", "verb.contact", "verb.creation", "verb.emotion", "verb.motion",
+	"verb.perception", "verb.possession", "verb.social", "verb.stative", "verb.weather", "adj.ppl",
+}
+
+// LexfileOf - returns the lexicographer file a dictionary file stands for, if its name is one.
+//
+// Usage:
+//
+//	LexfileOf("english/noun.animal.yaml") // noun.animal
+//	LexfileOf("glossary.csv")             // ""
+func LexfileOf(file string) string {
+	name := path.Base(file)
+
+	category, rest, ok := strings.Cut(name, ".")
+	if !ok {
+		return ""
+	}
+
+	switch category {
+	case "noun", "verb", "adj", "adv":
+	default:
+		return ""
+	}
+
+	topic, _, _ := strings.Cut(rest, ".")
+	if topic == "" {
+		return ""
+	}
+
+	return category + "." + topic
+}
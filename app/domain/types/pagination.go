@@ -0,0 +1,73 @@
+package types
+
+import (
+	"cmp"
+	"errors"
+)
+
+const (
+	DefaultLimit = 25  // matches per page, unless asked for
+	MaxLimit     = 100 // matches per page, at most
+)
+
+// ErrInvalidPage - the cursor or limit asked for cannot be paged by.
+var ErrInvalidPage = errors.New("invalid page")
+
+// Cursor - the sort key of the last match of a page, which the next page starts after.
+//
+// Matches are ordered best score first, then by word, id, and synset, so no two of them
+// share a key. Matches of searches without a description all score 0.
+type Cursor struct {
+	Rank     float64 `json:"r"`
+	Word     string  `json:"w"`
+	Id       int     `json:"i"`
+	SynsetId string  `json:"s"`
+}
+
+// Compare - returns -1 when c comes before o, 1 when it comes after, and 0 when they are the same.
+func (c Cursor) Compare(o Cursor) int {
+	/* Higher ranks come first */
+	return cmp.Or(
+		cmp.Compare(o.Rank, c.Rank),
+		cmp.Compare(c.Word, o.Word),
+		cmp.Compare(c.Id, o.Id),
+		cmp.Compare(c.SynsetId, o.SynsetId),
+	)
+}
+
+// Key - where the match is ordered among others.
+func (m MatchingWord) Key() Cursor {
+	var key = Cursor{Word: m.Word, Id: m.Id, SynsetId: m.SynsetId}
+	if m.Score != nil {
+		key.Rank = m.Score.Total
+	}
+	return key
+}
+
+// PageSize - how many matches the search returns, DefaultLimit when no limit was given.
+func (in GetDescribedWordsInput) PageSize() int {
+	if in.Limit <= 0 {
+		return DefaultLimit
+	}
+	return min(in.Limit, MaxLimit)
+}
+
+// Page - keeps the matches, sorted by their keys, that come after the cursor, up to size of them.
+// When more remain, Next is the key of the last one kept.
+func (w *WordMatches) Page(after *Cursor, size int) {
+	var page = w.MatchingWords[:0]
+
+	for _, m := range w.MatchingWords {
+		if after == nil || m.Key().Compare(*after) > 0 {
+			page = append(page, m)
+		}
+	}
+
+	w.MatchingWords, w.HasMore = page[:min(len(page), size)], len(page) > size
+	w.Next = nil
+
+	if w.HasMore {
+		next := w.MatchingWords[len(w.MatchingWords)-1].Key()
+		w.Next = &next
+	}
+}
@@ -0,0 +1,15 @@
+package types
+
+import "fmt"
+
+// QueryError - a description that is not a valid search query.
+// Position is the byte offset of the part of the query the message is about.
+type QueryError struct {
+	Query    string `json:"query"`
+	Position int    `json:"position"`
+	Message  string `json:"message"`
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Position, e.Message)
+}
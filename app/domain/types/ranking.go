@@ -0,0 +1,75 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// Ranking - the weights search matches are ordered by.
+//
+// Relevance is BM25, with matches in each field weighed apart. Priors then favor
+// common words over obscure senses: a match scores more the earlier its word is listed
+// among the members of its synset (WordNet lists the most frequent first), the more
+// senses its word has, and the more of its headword and gloss the query covers.
+// Relevance is on the scale of each engine: ts_rank_cd in PostgreSQL ranges well below BM25.
+type Ranking struct {
+	Word       float64 `json:"word" mapstructure:"word"`             // i.e 4, matches in the headword
+	Definition float64 `json:"definition" mapstructure:"definition"` // i.e 1, matches in the gloss
+	Examples   float64 `json:"examples" mapstructure:"examples"`     // i.e 0.5, matches in example sentences
+
+	SenseOrder float64 `json:"sense_order" mapstructure:"sense_order"`
+	Polysemy   float64 `json:"polysemy" mapstructure:"polysemy"`
+	Headword   float64 `json:"headword" mapstructure:"headword"`
+	Coverage   float64 `json:"coverage" mapstructure:"coverage"`
+}
+
+// DefaultRanking - the weights searches are ranked by unless configured otherwise.
+var DefaultRanking = Ranking{
+	Word:       4,
+	Definition: 1,
+	Examples:   0.5,
+	SenseOrder: 2,
+	Polysemy:   2,
+	Headword:   4,
+	Coverage:   4,
+}
+
+// Score - how a match was ranked, higher being better.
+// Total is the relevance plus what each prior, as weighed, added to it.
+type Score struct {
+	Total      float64 `json:"total"`
+	Relevance  float64 `json:"relevance"`
+	SenseOrder float64 `json:"sense_order"`
+	Polysemy   float64 `json:"polysemy"`
+	Headword   float64 `json:"headword"`
+	Coverage   float64 `json:"coverage"`
+}
+
+// Weights - the weights the search ranks its matches by.
+func (in GetDescribedWordsInput) Weights() Ranking {
+	if in.Ranking == nil {
+		return DefaultRanking
+	}
+	return *in.Ranking
+}
+
+// Check - returns an error unless every weight is a finite number of at least 0.
+func (r Ranking) Check() error {
+	weights := map[string]float64{
+		"word":        r.Word,
+		"definition":  r.Definition,
+		"examples":    r.Examples,
+		"sense_order": r.SenseOrder,
+		"polysemy":    r.Polysemy,
+		"headword":    r.Headword,
+		"coverage":    r.Coverage,
+	}
+
+	for name, weight := range weights {
+		if weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return fmt.Errorf("invalid %s weight %v: weights are finite numbers of at least 0", name, weight)
+		}
+	}
+
+	return nil
+}
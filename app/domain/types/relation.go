@@ -0,0 +1,82 @@
+package types
+
+// RelationType names a WordNet pointer between two synsets.
+type RelationType string
+
+type (
+	Relation struct {
+		Source string       `json:"source"` // i.e 00003552-s
+		Target string       `json:"target"` // i.e 00003356-a
+		Type   RelationType `json:"type"`   // i.e similar
+	}
+
+	GetRelatedWordsInput struct {
+		Word         string       `json:"word"`
+		PartOfSpeech PartOfSpeech `json:"part_of_speech"`
+		RelationType RelationType `json:"relation"`
+	}
+
+	RelatedWord struct {
+		Word         string       `json:"word"`
+		PartOfSpeech PartOfSpeech `json:"part_of_speech"`
+		Definition   string       `json:"definition"`
+		Relation     RelationType `json:"relation"`
+
+		// When true, the related word points at the requested word
+		// (i.e a hyponym found by following hypernyms backwards).
+		Inverse bool `json:"inverse,omitempty"`
+	}
+
+	RelatedWords struct {
+		Word      string        `json:"word"`
+		Relations []RelatedWord `json:"relations"`
+	}
+)
+
+const (
+	Also             RelationType = "also"
+	Attribute        RelationType = "attribute"
+	Causes           RelationType = "causes"
+	DomainRegion     RelationType = "domain_region"
+	DomainTopic      RelationType = "domain_topic"
+	Entails          RelationType = "entails"
+	Exemplifies      RelationType = "exemplifies"
+	Hypernym         RelationType = "hypernym"
+	InstanceHypernym RelationType = "instance_hypernym"
+	MeroMember       RelationType = "mero_member"
+	MeroPart         RelationType = "mero_part"
+	MeroSubstance    RelationType = "mero_substance"
+	Similar          RelationType = "similar"
+)
+
+// InverseRelations - Global WordNet relation types that point the other way of a pointer the
+// dictionary stores, i.e a synset is the hypernym of each of its hyponyms.
+var InverseRelations = map[RelationType]RelationType{
+	"hyponym":           Hypernym,
+	"instance_hyponym":  InstanceHypernym,
+	"holo_member":       MeroMember,
+	"holo_part":         MeroPart,
+	"holo_substance":    MeroSubstance,
+	"has_domain_region": DomainRegion,
+	"has_domain_topic":  DomainTopic,
+	"is_caused_by":      Causes,
+	"is_entailed_by":    Entails,
+	"is_exemplified_by": Exemplifies,
+}
+
+// RelationTypes lists every pointer type understood by the dictionary.
+var RelationTypes = []RelationType{
+	Also,
+	Attribute,
+	Causes,
+	DomainRegion,
+	DomainTopic,
+	Entails,
+	Exemplifies,
+	Hypernym,
+	InstanceHypernym,
+	MeroMember,
+	MeroPart,
+	MeroSubstance,
+	Similar,
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Source - a dictionary file as of its last ingestion.
+//
+// Files whose hash has not changed since are not ingested again.
+type Source struct {
+	Name       string    `json:"name"`    // i.e noun.animal.yaml
+	Hash       string    `json:"hash"`    // i.e sha256 of the file contents
+	Synsets    int       `json:"synsets"` // i.e 7516
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// SourceUpdate - the outcome of re-ingesting a file: its new state and the synsets
+// it no longer contains.
+type SourceUpdate struct {
+	Source Source
+	Stale  []string // i.e 02084071-n
+}
+
+// Checksum - fingerprints the synset, so unmodified synsets are not written again.
+func (s NewSynsetInput) Checksum() string {
+	b, _ := json.Marshal(s)
+	return fmt.Sprintf("%x", sha1.Sum(b))
+}
+
+// IndexMaintenance - what IndexWords does to the full-text index, which writes keep up to date.
+type IndexMaintenance string
+
+const (
+	RebuildIndex  IndexMaintenance = "rebuild"  // reindexes every entry, i.e to repair an index out of step
+	OptimizeIndex IndexMaintenance = "optimize" // merges the index into as few parts as it can, for faster searches
+)
@@ -0,0 +1,134 @@
+// Package highlight finds where a full-text query matched the definition and examples
+// of a search match, alike in every dictionary backend.
+//
+// Usage:
+//
+//	query, _ := search.Parse(`"sea lion" OR seal*`)
+//	phrases := search.Phrases(query)
+//	highlight.Find("the sea lion and the walrus", phrases) // [{4 12}]
+//	highlight.Match(&match, phrases, examples)
+package highlight
+
+import (
+	"strings"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/ranking"
+)
+
+// Words - how long snippets are, at most.
+const Words = 16
+
+const ellipsis = "…"
+
+// Find - where any of the phrases occurs in the text, in order. Where phrases overlap,
+// the longest one that starts first is highlighted.
+func Find(text string, phrases [][]string) []types.Span {
+	var spans []types.Span
+	var tokens = ranking.Tokens(text)
+
+	for i := 0; i < len(tokens); {
+		var longest int
+		for _, phrase := range phrases {
+			if len(phrase) > longest && matches(tokens[i:], phrase) {
+				longest = len(phrase)
+			}
+		}
+
+		if longest == 0 {
+			i++
+			continue
+		}
+
+		spans = append(spans, types.Span{Start: tokens[i].Start, End: tokens[i+longest-1].End})
+		i += longest
+	}
+
+	return spans
+}
+
+// matches - whether the tokens start with the phrase. Its last term may be a prefix (i.e seal*).
+func matches(tokens []ranking.Token, phrase []string) bool {
+	if len(tokens) < len(phrase) {
+		return false
+	}
+
+	for i, term := range phrase {
+		prefix, ok := strings.CutSuffix(term, "*")
+		if ok && i == len(phrase)-1 {
+			if !strings.HasPrefix(tokens[i].Term, prefix) {
+				return false
+			}
+		} else if tokens[i].Term != term {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Snippet - at most n words of the text, around its first highlight, with an ellipsis where
+// it was cut. Spans are moved along with the text, and those cut off are left out.
+func Snippet(text string, spans []types.Span, n int) types.Passage {
+	var tokens = ranking.Tokens(text)
+	if len(tokens) <= n || len(spans) == 0 {
+		return types.Passage{Text: text, Highlights: spans}
+	}
+
+	/* The first highlight comes after a quarter of the words, unless the text starts sooner */
+	var first int
+	for first < len(tokens) && tokens[first].End <= spans[0].Start {
+		first++
+	}
+
+	start := max(0, min(first-n/4, len(tokens)-n))
+	end := start + n
+
+	from, to := tokens[start].Start, tokens[end-1].End
+	if start == 0 {
+		from = 0
+	}
+	if end == len(tokens) {
+		to = len(text)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString(ellipsis)
+	}
+
+	offset := b.Len() - from
+	b.WriteString(text[from:to])
+
+	if to < len(text) {
+		b.WriteString(ellipsis)
+	}
+
+	var passage = types.Passage{Text: b.String(), Highlights: []types.Span{}}
+
+	for _, span := range spans {
+		if span.Start >= from && span.End <= to {
+			passage.Highlights = append(passage.Highlights, types.Span{Start: span.Start + offset, End: span.End + offset})
+		}
+	}
+
+	return passage
+}
+
+// Match - highlights the definition of a match, and takes its snippet from the definition
+// or, when the query did not match it, from the first of the examples the query matched.
+func Match(m *types.MatchingWord, phrases [][]string, examples []string) {
+	if m.Highlights = Find(m.Definition, phrases); len(m.Highlights) > 0 {
+		snippet := Snippet(m.Definition, m.Highlights, Words)
+		m.Snippet = &snippet
+		return
+	}
+
+	for _, example := range examples {
+		if spans := Find(example, phrases); len(spans) > 0 {
+			snippet := Snippet(example, spans, Words)
+			m.Snippet = &snippet
+			return
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package highlight_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/highlight"
+	"github.com/oleoneto/redic/app/pkg/search"
+)
+
+// phrases - the phrases of a query, as backends give them to highlight.
+func phrases(t *testing.T, query string) [][]string {
+	t.Helper()
+
+	e, err := search.Parse(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return search.Phrases(e)
+}
+
+func Test_Find(t *testing.T) {
+	tests := []struct {
+		text  string
+		query string
+		want  []types.Span
+	}{
+		{text: "the sea lion and the walrus", query: `"sea lion" OR seal*`, want: []types.Span{{Start: 4, End: 12}}},
+		{text: "seals and sea lions", query: `"sea lion" OR seal*`, want: []types.Span{{Start: 0, End: 5}}},
+		{text: "a young dog", query: "young dog", want: []types.Span{{Start: 2, End: 7}, {Start: 8, End: 11}}},
+		{text: "a dog collar", query: `dog "dog collar"`, want: []types.Span{{Start: 2, End: 12}}},
+		{text: "Café au lait", query: "cafe", want: []types.Span{{Start: 0, End: 5}}},
+		{text: "a young dog", query: "cat", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := highlight.Find(tt.text, phrases(t, tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q, %q) = %v, want %v", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Snippet(t *testing.T) {
+	text := "one two three four five six seven eight nine ten"
+
+	tests := []struct {
+		name  string
+		spans []types.Span
+		n     int
+		want  types.Passage
+	}{
+		{
+			name:  "short text",
+			spans: []types.Span{{Start: 0, End: 3}},
+			n:     16,
+			want:  types.Passage{Text: text, Highlights: []types.Span{{Start: 0, End: 3}}},
+		},
+		{
+			name:  "highlight at the start",
+			spans: []types.Span{{Start: 4, End: 7}},
+			n:     4,
+			want:  types.Passage{Text: "one two three four…", Highlights: []types.Span{{Start: 4, End: 7}}},
+		},
+		{
+			name:  "highlight in the middle",
+			spans: []types.Span{{Start: 24, End: 27}, {Start: 45, End: 48}},
+			n:     4,
+			want:  types.Passage{Text: "…five six seven eight…", Highlights: []types.Span{{Start: 8, End: 11}}},
+		},
+		{
+			name:  "highlight at the end",
+			spans: []types.Span{{Start: 45, End: 48}},
+			n:     4,
+			want:  types.Passage{Text: "…seven eight nine ten", Highlights: []types.Span{{Start: 20, End: 23}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight.Snippet(text, tt.spans, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Snippet() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Match(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		definition string
+		examples   []string
+		highlights []types.Span
+		snippet    *types.Passage
+	}{
+		{
+			name:       "definition",
+			query:      "young dog",
+			definition: "a young dog",
+			examples:   []string{"the dog barked"},
+			highlights: []types.Span{{Start: 2, End: 7}, {Start: 8, End: 11}},
+			snippet:    &types.Passage{Text: "a young dog", Highlights: []types.Span{{Start: 2, End: 7}, {Start: 8, End: 11}}},
+		},
+		{
+			name:       "example",
+			query:      "barked",
+			definition: "a young dog",
+			examples:   []string{"the puppy slept", "the puppy barked"},
+			snippet:    &types.Passage{Text: "the puppy barked", Highlights: []types.Span{{Start: 10, End: 16}}},
+		},
+		{
+			name:       "neither",
+			query:      "cat",
+			definition: "a young dog",
+			examples:   []string{"the puppy barked"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := types.MatchingWord{Definition: tt.definition}
+			highlight.Match(&m, phrases(t, tt.query), tt.examples)
+
+			if !reflect.DeepEqual(m.Highlights, tt.highlights) {
+				t.Errorf("Highlights = %v, want %v", m.Highlights, tt.highlights)
+			}
+
+			if !reflect.DeepEqual(m.Snippet, tt.snippet) {
+				t.Errorf("Snippet = %#v, want %#v", m.Snippet, tt.snippet)
+			}
+		})
+	}
+}
+
+func Test_Markup_Mark(t *testing.T) {
+	spans := []types.Span{{Start: 2, End: 7}, {Start: 8, End: 11}}
+
+	tests := []struct {
+		name   string
+		text   string
+		markup types.Markup
+		want   string
+	}{
+		{name: "html", text: "a young dog", markup: types.HTMLMarkup, want: "a <mark>young</mark> <mark>dog</mark>"},
+		{name: "html escaped", text: "& young dog", markup: types.HTMLMarkup, want: "&amp; <mark>young</mark> <mark>dog</mark>"},
+		{name: "ansi", text: "a young dog", markup: types.ANSIMarkup, want: "a \x1b[1;33myoung\x1b[0m \x1b[1;33mdog\x1b[0m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.markup.Mark(tt.text, spans); got != tt.want {
+				t.Errorf("Mark() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := types.HTMLMarkup.Mark("a dog", []types.Span{{Start: 2, End: 9}}); strings.Contains(got, "<mark>") {
+		t.Errorf("Mark() = %q, spans past the text should be left out", got)
+	}
+}
@@ -0,0 +1,355 @@
+package ingestion
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+)
+
+// SynsetWriter persists a batch of synsets, all or nothing.
+type SynsetWriter interface {
+	CreateSynsets(context.Context, []types.NewSynsetInput) error
+}
+
+// SourceLedger remembers what was ingested from each file, so that files can be
+// re-ingested incrementally. Writers that implement it are used as the ledger.
+type SourceLedger interface {
+	GetSource(context.Context, string) (types.Source, error)
+	GetSourceChecksums(context.Context, string) (map[string]string, error)
+	RecordSource(context.Context, types.SourceUpdate) error
+}
+
+// Indexer keeps a full-text index up to date as synsets are written. Writers that
+// implement it can leave the index behind while a pipeline writes, and rebuild it once done.
+type Indexer interface {
+	SuspendIndexing(context.Context) (func(context.Context) error, error)
+}
+
+// Progress is notified as files are parsed and batches are written.
+type Progress interface {
+	FileStarted(file string)
+	FileFinished(file string, synsets int, err error)
+	BatchWritten(synsets int, err error)
+}
+
+type Options struct {
+	// Number of files parsed concurrently. Defaults to the number of CPUs.
+	Workers int
+
+	// Number of synsets written per transaction. Defaults to 1000.
+	BatchSize int
+
+	// BCP-47 language of entries whose file does not declare one. Defaults to English.
+	Language string
+
+	// Hashes the contents of a file. Files are only re-ingested incrementally when set
+	// and the writer is a SourceLedger.
+	Checksum func(dir string, file fs.DirEntry) (string, error)
+
+	// Re-ingests every file and synset, even those that have not changed.
+	Force bool
+
+	// Rebuilds the full-text index once every synset is written, instead of as each is, when
+	// the writer is an Indexer. Faster for large imports, slower when few synsets change.
+	DeferIndexing bool
+
+	Progress Progress
+}
+
+// Pipeline moves dictionary entries from a parser into a writer in three stages:
+//
+//	parse (one worker per file) → transform (entry to synset) → batched transactional write
+//
+// Writes happen on a single goroutine, so backends that allow only one writer
+// at a time (i.e SQLite) are never contended.
+type Pipeline struct {
+	parser  protocols.FileParserProtocol
+	writer  SynsetWriter
+	ledger  SourceLedger
+	options Options
+}
+
+// item - a synset on its way to the writer or, once a file has been parsed, what
+// should be recorded about it.
+type item struct {
+	synset types.NewSynsetInput
+	source *types.SourceUpdate
+}
+
+func NewPipeline(parser protocols.FileParserProtocol, writer SynsetWriter, options Options) *Pipeline {
+	if options.Workers <= 0 {
+		options.Workers = runtime.NumCPU()
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1000
+	}
+
+	if options.Language == "" {
+		options.Language = types.DefaultLanguage
+	}
+
+	if options.Progress == nil {
+		options.Progress = silentProgress{}
+	}
+
+	var pipeline = &Pipeline{parser: parser, writer: writer, options: options}
+
+	if ledger, ok := writer.(SourceLedger); ok && options.Checksum != nil {
+		pipeline.ledger = ledger
+	}
+
+	return pipeline
+}
+
+// Run - ingests every file and reports what was written, skipped or failed.
+//
+// Entries without members, or without both definitions and an interlingual index (ILI),
+// are skipped; entries linked through the ILI borrow glosses from other languages. A batch that fails to be
+// written is rolled back and its synsets are counted as errored; later batches
+// are still attempted. Run only returns an error if the context ends early.
+//
+// When the writer is a SourceLedger, files whose hash has not changed since their last
+// ingestion are skipped, and so are synsets whose checksum has not changed. Synsets a
+// file no longer contains are deleted once all of its other synsets have been written.
+func (p *Pipeline) Run(ctx context.Context, dir string, files []fs.DirEntry) (Report, error) {
+	var report = Report{Files: len(files), Errors: []string{}}
+	var start = time.Now()
+	var mu sync.Mutex
+
+	var resume = func(context.Context) error { return nil }
+
+	if indexer, ok := p.writer.(Indexer); ok && p.options.DeferIndexing {
+		var err error
+		if resume, err = indexer.SuspendIndexing(ctx); err != nil {
+			return report, err
+		}
+	}
+
+	record := func(f func(*Report)) {
+		mu.Lock()
+		defer mu.Unlock()
+		f(&report)
+	}
+
+	queue := make(chan fs.DirEntry)
+	synsets := make(chan item, p.options.BatchSize)
+
+	// Stage 1 + 2: parse files concurrently and transform their entries
+	var workers sync.WaitGroup
+	for i := 0; i < p.options.Workers; i++ {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			for file := range queue {
+				p.parse(ctx, dir, file, synsets, record)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(queue)
+
+		for _, file := range files {
+			select {
+			case <-ctx.Done():
+				return
+			case queue <- file:
+			}
+		}
+	}()
+
+	go func() {
+		workers.Wait()
+		close(synsets)
+	}()
+
+	// Stage 3: write in batches, one transaction each
+	batch := make([]types.NewSynsetInput, 0, p.options.BatchSize)
+	failed := map[string]bool{} // files with synsets in a failed batch
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		err := p.writer.CreateSynsets(ctx, batch)
+		p.options.Progress.BatchWritten(len(batch), err)
+
+		record(func(r *Report) {
+			if err != nil {
+				for _, synset := range batch {
+					failed[synset.Source] = true
+				}
+
+				r.Errored += len(batch)
+				r.Errors = append(r.Errors, fmt.Sprintf("batch of %d synsets starting at %s: %v", len(batch), batch[0].Id, err))
+				return
+			}
+
+			for _, synset := range batch {
+				r.add(synset)
+			}
+		})
+
+		batch = batch[:0]
+	}
+
+	for item := range synsets {
+		if item.source != nil {
+			flush()
+			p.record(ctx, *item.source, failed[item.source.Source.Name], record)
+			continue
+		}
+
+		batch = append(batch, item.synset)
+
+		if len(batch) == p.options.BatchSize {
+			flush()
+		}
+	}
+
+	if ctx.Err() == nil {
+		flush()
+	}
+
+	/* The index is brought back even when the context ended early */
+	if err := resume(context.WithoutCancel(ctx)); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("rebuilding the index: %v", err))
+	}
+
+	report.Elapsed = time.Since(start)
+
+	return report, ctx.Err()
+}
+
+func (p *Pipeline) parse(ctx context.Context, dir string, file fs.DirEntry, synsets chan<- item, record func(func(*Report))) {
+	p.options.Progress.FileStarted(file.Name())
+
+	var count int
+	var update *types.SourceUpdate
+	var previous map[string]string
+
+	fail := func(err error) {
+		record(func(r *Report) { r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", file.Name(), err)) })
+		p.options.Progress.FileFinished(file.Name(), count, err)
+	}
+
+	if p.ledger != nil {
+		hash, err := p.options.Checksum(dir, file)
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		known, err := p.ledger.GetSource(ctx, file.Name())
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		if known.Hash == hash && !p.options.Force {
+			record(func(r *Report) { r.Unchanged += known.Synsets })
+			p.options.Progress.FileFinished(file.Name(), 0, nil)
+			return
+		}
+
+		if previous, err = p.ledger.GetSourceChecksums(ctx, file.Name()); err != nil {
+			fail(err)
+			return
+		}
+
+		update = &types.SourceUpdate{Source: types.Source{Name: file.Name(), Hash: hash}}
+	}
+
+	var seen = map[string]bool{}
+	entries, errs := p.parser.StreamFile(ctx, dir, file)
+
+	for entry := range entries {
+		if len(entry.Members) == 0 || (len(entry.Definitions) == 0 && entry.Identifier == "") {
+			record(func(r *Report) { r.Skipped++ })
+			continue
+		}
+
+		if entry.SkippedRelations > 0 {
+			record(func(r *Report) { r.SkippedRelations += entry.SkippedRelations })
+		}
+
+		if entry.Language == "" {
+			entry.Language = p.options.Language
+		}
+
+		synset := entry.Synset()
+		synset.Source = file.Name()
+
+		if update != nil {
+			seen[synset.Id] = true
+			update.Source.Synsets++
+
+			if checksum, ok := previous[synset.Id]; ok && checksum == synset.Checksum() && !p.options.Force {
+				record(func(r *Report) { r.Unchanged++ })
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case synsets <- item{synset: synset}:
+			count++
+		}
+	}
+
+	err := <-errs
+	if err != nil {
+		record(func(r *Report) {
+			r.Errors = append(r.Errors, err.Error())
+		})
+	}
+
+	// A file that could not be read whole keeps its previous synsets
+	if update != nil && err == nil && ctx.Err() == nil {
+		for id := range previous {
+			if !seen[id] {
+				update.Stale = append(update.Stale, id)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case synsets <- item{source: update}:
+		}
+	}
+
+	p.options.Progress.FileFinished(file.Name(), count, err)
+}
+
+// record - deletes the synsets a file no longer contains and records its new hash,
+// unless some of its synsets could not be written.
+func (p *Pipeline) record(ctx context.Context, update types.SourceUpdate, failed bool, record func(func(*Report))) {
+	if failed {
+		return
+	}
+
+	if err := p.ledger.RecordSource(ctx, update); err != nil {
+		record(func(r *Report) {
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", update.Source.Name, err))
+		})
+		return
+	}
+
+	record(func(r *Report) { r.Deleted += len(update.Stale) })
+}
+
+type silentProgress struct{}
+
+func (silentProgress) FileStarted(string)              {}
+func (silentProgress) FileFinished(string, int, error) {}
+func (silentProgress) BatchWritten(int, error)         {}
@@ -0,0 +1,302 @@
+package ingestion_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"reflect"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/ingestion"
+	"github.com/oleoneto/redic/app/pkg/parsers"
+)
+
+type writer struct {
+	mu      sync.Mutex
+	batches [][]types.NewSynsetInput
+	fail    string
+}
+
+func (w *writer) CreateSynsets(_ context.Context, batch []types.NewSynsetInput) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, synset := range batch {
+		if synset.Id == w.fail {
+			return errors.New("constraint failed")
+		}
+	}
+
+	w.batches = append(w.batches, append([]types.NewSynsetInput{}, batch...))
+	return nil
+}
+
+// indexer - a writer that logs when its index is suspended, written to and rebuilt.
+type indexer struct {
+	writer
+	events []string
+}
+
+func (i *indexer) CreateSynsets(ctx context.Context, batch []types.NewSynsetInput) error {
+	i.events = append(i.events, "write")
+	return i.writer.CreateSynsets(ctx, batch)
+}
+
+func (i *indexer) SuspendIndexing(context.Context) (func(context.Context) error, error) {
+	i.events = append(i.events, "suspend")
+
+	return func(context.Context) error {
+		i.events = append(i.events, "rebuild")
+		return nil
+	}, nil
+}
+
+func Test_Pipeline_Run(t *testing.T) {
+	fsys := fstest.MapFS{
+		"english/noun.animal.yaml": {Data: []byte(`02084071-n:
+  definition:
+  - a member of the genus Canis
+  example:
+  - the dog barked all night
+  hypernym:
+  - 02083346-n
+  members:
+  - dog
+  - domestic dog
+  partOfSpeech: n
+02083346-n:
+  definition:
+  - any of various fissiped mammals with nonretractile claws
+  members:
+  - canine
+  partOfSpeech: n
+`)},
+		"english/verb.weather.yaml": {Data: []byte(`02758033-v:
+  definition:
+  - fall vertically, sporadically, or naturally
+  members:
+  - rain
+  partOfSpeech: v
+02758960-v:
+  definition: []
+  members:
+  - drizzle
+  partOfSpeech: v
+`)},
+	}
+
+	parser := parsers.StreamingParser(
+		func(dir string) ([]fs.DirEntry, error) { return fs.ReadDir(fsys, dir) },
+		func(name string) (io.ReadCloser, error) { return fsys.Open(name) },
+	)
+
+	files := parser.LoadFiles(context.Background(), "english")
+
+	t.Run("batches", func(t *testing.T) {
+		w := &writer{}
+
+		report, err := ingestion.NewPipeline(parser, w, ingestion.Options{BatchSize: 2}).Run(context.Background(), "english", files)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		want := ingestion.Report{Files: 2, Synsets: 3, Words: 4, Definitions: 3, Examples: 1, Relations: 1, Skipped: 1}
+		report.Elapsed, report.Errors = 0, nil
+
+		if !reflect.DeepEqual(report, want) {
+			t.Errorf("Run() = %+v, want %+v", report, want)
+		}
+
+		if len(w.batches) != 2 {
+			t.Errorf("Run() wrote %d batches, want 2", len(w.batches))
+		}
+	})
+
+	t.Run("deferred indexing", func(t *testing.T) {
+		tests := []struct {
+			deferIndexing bool
+			want          []string
+		}{
+			{deferIndexing: false, want: []string{"write", "write"}},
+			{deferIndexing: true, want: []string{"suspend", "write", "write", "rebuild"}},
+		}
+
+		for _, tt := range tests {
+			w := &indexer{}
+
+			if _, err := ingestion.NewPipeline(parser, w, ingestion.Options{BatchSize: 2, DeferIndexing: tt.deferIndexing}).Run(context.Background(), "english", files); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(w.events, tt.want) {
+				t.Errorf("Run(DeferIndexing: %v) = %v, want %v", tt.deferIndexing, w.events, tt.want)
+			}
+		}
+	})
+
+	t.Run("errored batch", func(t *testing.T) {
+		w := &writer{fail: "02758033-v"}
+
+		report, err := ingestion.NewPipeline(parser, w, ingestion.Options{BatchSize: 1}).Run(context.Background(), "english", files)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		if report.Errored != 1 || report.Synsets != 2 || !report.Failed() {
+			t.Errorf("Run() = %+v, want 1 errored and 2 written synsets", report)
+		}
+	})
+}
+
+type ledger struct {
+	writer
+	sources   map[string]types.Source
+	checksums map[string]map[string]string
+	stale     []string
+}
+
+func (l *ledger) CreateSynsets(ctx context.Context, batch []types.NewSynsetInput) error {
+	if err := l.writer.CreateSynsets(ctx, batch); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, synset := range batch {
+		if l.checksums[synset.Source] == nil {
+			l.checksums[synset.Source] = map[string]string{}
+		}
+		l.checksums[synset.Source][synset.Id] = synset.Checksum()
+	}
+
+	return nil
+}
+
+func (l *ledger) GetSource(_ context.Context, name string) (types.Source, error) {
+	return l.sources[name], nil
+}
+
+func (l *ledger) GetSourceChecksums(_ context.Context, name string) (map[string]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var checksums = map[string]string{}
+	for id, checksum := range l.checksums[name] {
+		checksums[id] = checksum
+	}
+
+	return checksums, nil
+}
+
+func (l *ledger) RecordSource(_ context.Context, update types.SourceUpdate) error {
+	l.sources[update.Source.Name] = update.Source
+	l.stale = append(l.stale, update.Stale...)
+
+	for _, id := range update.Stale {
+		delete(l.checksums[update.Source.Name], id)
+	}
+
+	return nil
+}
+
+func Test_Pipeline_Run_Incremental(t *testing.T) {
+	fsys := fstest.MapFS{
+		"english/noun.animal.yaml": {Data: []byte(`02084071-n:
+  definition:
+  - a member of the genus Canis
+  members:
+  - dog
+  partOfSpeech: n
+02083346-n:
+  definition:
+  - any of various fissiped mammals with nonretractile claws
+  members:
+  - canine
+  partOfSpeech: n
+`)},
+	}
+
+	opener := func(name string) (io.ReadCloser, error) { return fsys.Open(name) }
+	parser := parsers.StreamingParser(func(dir string) ([]fs.DirEntry, error) { return fs.ReadDir(fsys, dir) }, opener)
+
+	l := &ledger{sources: map[string]types.Source{}, checksums: map[string]map[string]string{}}
+
+	run := func(options ingestion.Options) ingestion.Report {
+		t.Helper()
+
+		options.Checksum = parsers.Checksum(opener)
+		files := parser.LoadFiles(context.Background(), "english")
+
+		report, err := ingestion.NewPipeline(parser, l, options).Run(context.Background(), "english", files)
+		if err != nil || report.Failed() {
+			t.Fatalf("Run() error = %v, %v", err, report.Errors)
+		}
+
+		report.Elapsed, report.Errors = 0, nil
+		return report
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		options ingestion.Options
+		want    ingestion.Report
+	}{
+		{
+			name: "first import",
+			want: ingestion.Report{Files: 1, Synsets: 2, Words: 2, Definitions: 2},
+		},
+		{
+			name: "unchanged file",
+			want: ingestion.Report{Files: 1, Unchanged: 2},
+		},
+		{
+			name:    "forced",
+			options: ingestion.Options{Force: true},
+			want:    ingestion.Report{Files: 1, Synsets: 2, Words: 2, Definitions: 2},
+		},
+		{
+			name: "changed file",
+			data: `02084071-n:
+  definition:
+  - a member of the genus Canis
+  members:
+  - dog
+  - domestic dog
+  partOfSpeech: n
+02121620-n:
+  definition:
+  - feline mammal usually having thick soft fur
+  members:
+  - cat
+  partOfSpeech: n
+`,
+			want: ingestion.Report{Files: 1, Synsets: 2, Words: 3, Definitions: 2, Deleted: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.data != "" {
+				fsys["english/noun.animal.yaml"] = &fstest.MapFile{Data: []byte(tt.data)}
+			}
+
+			if got := run(tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(l.stale, []string{"02083346-n"}) {
+		t.Errorf("Run() deleted %v, want [02083346-n]", l.stale)
+	}
+
+	if got := l.sources["noun.animal.yaml"].Synsets; got != 2 {
+		t.Errorf("Run() recorded %d synsets, want 2", got)
+	}
+}
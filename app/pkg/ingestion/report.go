@@ -0,0 +1,72 @@
+package ingestion
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/oleoneto/redic/app/domain/types"
+)
+
+// Report - a summary of what an ingestion wrote, skipped or failed to write.
+type Report struct {
+	Files            int           `json:"files" yaml:"files"`
+	Synsets          int           `json:"synsets" yaml:"synsets"`
+	Words            int           `json:"words" yaml:"words"`
+	Definitions      int           `json:"definitions" yaml:"definitions"`
+	Examples         int           `json:"examples" yaml:"examples"`
+	Relations        int           `json:"relations" yaml:"relations"`
+	Unchanged        int           `json:"unchanged" yaml:"unchanged"`
+	Deleted          int           `json:"deleted" yaml:"deleted"`
+	Skipped          int           `json:"skipped" yaml:"skipped"`
+	SkippedRelations int           `json:"skipped_relations" yaml:"skipped_relations"` // read, but not stored (i.e antonyms between senses)
+	Errored          int           `json:"errored" yaml:"errored"`
+	Errors           []string      `json:"errors" yaml:"errors"`
+	Elapsed          time.Duration `json:"elapsed_ns" yaml:"elapsed_ns"`
+}
+
+func (r *Report) add(synset types.NewSynsetInput) {
+	r.Synsets++
+	r.Words += len(synset.Members)
+	r.Definitions += len(synset.Definitions)
+	r.Examples += len(synset.Examples)
+	r.Relations += len(synset.Relations)
+}
+
+// Failed - returns true if any file or batch could not be ingested.
+func (r Report) Failed() bool { return len(r.Errors) != 0 }
+
+func (r Report) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d files ingested in %v\n", r.Files, r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(&b, "  synsets:           %d\n", r.Synsets)
+	fmt.Fprintf(&b, "  words:             %d\n", r.Words)
+	fmt.Fprintf(&b, "  definitions:       %d\n", r.Definitions)
+	fmt.Fprintf(&b, "  examples:          %d\n", r.Examples)
+	fmt.Fprintf(&b, "  relations:         %d\n", r.Relations)
+	fmt.Fprintf(&b, "  unchanged:         %d\n", r.Unchanged)
+	fmt.Fprintf(&b, "  deleted:           %d\n", r.Deleted)
+	fmt.Fprintf(&b, "  skipped:           %d\n", r.Skipped)
+	fmt.Fprintf(&b, "  skipped relations: %d\n", r.SkippedRelations)
+	fmt.Fprintf(&b, "  errored:           %d\n", r.Errored)
+
+	for _, err := range r.Errors {
+		fmt.Fprintf(&b, "  error: %s\n", err)
+	}
+
+	return b.String()
+}
+
+func (r Report) TableWriter() table.Writer {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleLight)
+	t.SetOutputMirror(nil) // Delegate printing to gout tool
+
+	t.SetTitle("ingestion")
+	t.AppendHeader(table.Row{"files", "synsets", "words", "definitions", "examples", "relations", "unchanged", "deleted", "skipped", "skipped relations", "errored", "elapsed"})
+	t.AppendRow(table.Row{r.Files, r.Synsets, r.Words, r.Definitions, r.Examples, r.Relations, r.Unchanged, r.Deleted, r.Skipped, r.SkippedRelations, r.Errored, r.Elapsed.Round(time.Millisecond)})
+
+	return t
+}
@@ -0,0 +1,340 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+)
+
+// Check - the name of a rule dictionary files are validated against.
+type Check string
+
+const (
+	Unreadable      Check = "unreadable"       // the file could not be parsed
+	DuplicateSynset Check = "duplicate-synset" // a synset id is defined more than once
+	NoMembers       Check = "no-members"       // a synset has no words
+	EmptyGloss      Check = "empty-gloss"      // a synset has no definition
+	PartOfSpeech    Check = "part-of-speech"   // a synset disagrees with its file name (noun.*.yaml holding a v)
+	DanglingTarget  Check = "dangling-target"  // a relation points at a synset that does not exist
+	HypernymCycle   Check = "hypernym-cycle"   // a synset is its own (indirect) hypernym
+	SelfReference   Check = "self-reference"   // a definition uses the word it defines
+)
+
+// Checks - every rule, in the order they are reported.
+var Checks = []Check{Unreadable, DuplicateSynset, NoMembers, EmptyGloss, PartOfSpeech, DanglingTarget, HypernymCycle, SelfReference}
+
+// Linter validates dictionary files before they are ingested.
+//
+// Synsets are checked one at a time as files are read. Relations are checked once
+// every file has been read, since their targets may live in another file.
+type Linter struct {
+	parser     protocols.FileParserProtocol
+	references map[string]bool
+	partial    bool
+}
+
+func NewLinter(parser protocols.FileParserProtocol) *Linter {
+	return &Linter{parser: parser}
+}
+
+// WithReferences - counts these synsets as relation targets too, i.e those of the rest
+// of the lexicon when only the files that changed are linted.
+func (l *Linter) WithReferences(ids map[string]bool) *Linter {
+	l.references = ids
+	return l
+}
+
+// WithPartial - lints files that are only part of a lexicon (i.e a single lexicographer file),
+// so relations pointing outside of them are warnings, unless there are references to check them against.
+func (l *Linter) WithPartial(partial bool) *Linter {
+	l.partial = partial
+	return l
+}
+
+// Synsets - the ids of every synset of the files, i.e to lint others WithReferences.
+func Synsets(ctx context.Context, parser protocols.FileParserProtocol, dir string, files []fs.DirEntry) (map[string]bool, error) {
+	var ids = map[string]bool{}
+
+	for _, file := range files {
+		entries, errs := parser.StreamFile(ctx, dir, file)
+
+		for entry := range entries {
+			ids[entry.Synset().Id] = true
+		}
+
+		if err := <-errs; err != nil {
+			return ids, fmt.Errorf("%s: %w", file.Name(), err)
+		}
+	}
+
+	return ids, ctx.Err()
+}
+
+// location - where a synset was defined.
+type location struct {
+	file     string
+	explicit bool
+}
+
+// Run - reads every file and reports the issues found. Run only returns an error
+// if the context ends early; files that cannot be parsed are reported as issues.
+func (l *Linter) Run(ctx context.Context, dir string, files []fs.DirEntry) (Report, error) {
+	var report = Report{Files: len(files), Issues: []Issue{}}
+
+	var synsets = map[string][]location{}
+	var relations []types.Relation
+	var sources = map[string]string{} // synset id → file
+
+	for _, file := range files {
+		entries, errs := l.parser.StreamFile(ctx, dir, file)
+
+		for entry := range entries {
+			report.Synsets++
+
+			synset := entry.Synset()
+			synsets[synset.Id] = append(synsets[synset.Id], location{file: file.Name(), explicit: entry.Explicit})
+			sources[synset.Id] = file.Name()
+			relations = append(relations, synset.Relations...)
+
+			report.Issues = append(report.Issues, checkEntry(file.Name(), synset)...)
+		}
+
+		if err := <-errs; err != nil {
+			report.add(Issue{File: file.Name(), Check: Unreadable, Severity: Error, Message: err.Error()})
+		}
+
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+	}
+
+	for _, id := range sortedKeys(synsets) {
+		for i, loc := range synsets[id] {
+			for _, previous := range synsets[id][:i] {
+				if previous.explicit == loc.explicit {
+					report.add(Issue{
+						File:     loc.file,
+						Synset:   id,
+						Check:    DuplicateSynset,
+						Severity: Error,
+						Message:  fmt.Sprintf("%s is already defined in %s", id, previous.file),
+					})
+					break
+				}
+			}
+		}
+	}
+
+	var hypernyms = map[string][]string{}
+
+	for _, relation := range relations {
+		if _, ok := synsets[relation.Target]; !ok && !l.references[relation.Target] {
+			issue := Issue{
+				File:     sources[relation.Source],
+				Synset:   relation.Source,
+				Check:    DanglingTarget,
+				Severity: Error,
+				Message:  fmt.Sprintf("%s points at %s, which does not exist", relation.Type, relation.Target),
+			}
+
+			/* The target may well be in a file that was left out */
+			if l.partial && l.references == nil {
+				issue.Severity = Warning
+				issue.Message = fmt.Sprintf("%s points at %s, which is not in the files linted", relation.Type, relation.Target)
+			}
+
+			report.add(issue)
+			continue
+		}
+
+		if relation.Type == types.Hypernym || relation.Type == types.InstanceHypernym {
+			hypernyms[relation.Source] = append(hypernyms[relation.Source], relation.Target)
+		}
+	}
+
+	for _, cycle := range cycles(hypernyms) {
+		report.add(Issue{
+			File:     sources[cycle[0]],
+			Synset:   cycle[0],
+			Check:    HypernymCycle,
+			Severity: Error,
+			Message:  fmt.Sprintf("hypernyms form a cycle: %s", strings.Join(append(cycle[:len(cycle):len(cycle)], cycle[0]), " → ")),
+		})
+	}
+
+	report.sort()
+
+	return report, nil
+}
+
+// checkEntry - validates a synset on its own.
+func checkEntry(file string, synset types.NewSynsetInput) []Issue {
+	var issues []Issue
+
+	add := func(check Check, severity Severity, format string, args ...any) {
+		issues = append(issues, Issue{File: file, Synset: synset.Id, Check: check, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(synset.Members) == 0 {
+		add(NoMembers, Error, "synset has no members")
+	}
+
+	if strings.TrimSpace(synset.Definition()) == "" {
+		// Synsets linked to the interlingual index borrow their gloss from other languages
+		if synset.ILI != "" {
+			add(EmptyGloss, Warning, "synset has no definition of its own (ili %s)", synset.ILI)
+		} else {
+			add(EmptyGloss, Error, "synset has no definition")
+		}
+	}
+
+	if expected := partsOfSpeech(file); len(expected) != 0 && !helpers.Contains(expected, synset.PartOfSpeech) {
+		add(PartOfSpeech, Error, "part of speech %q does not belong in %s (expected %s)", synset.PartOfSpeech, file, strings.Join(expected, " or "))
+	}
+
+	for _, member := range synset.Members {
+		if mentions(synset.Definition(), member) {
+			add(SelfReference, Warning, "definition uses the word it defines: %q", member)
+			break
+		}
+	}
+
+	return issues
+}
+
+// partsOfSpeech - the parts of speech a file may hold, as told by its name
+// (i.e noun.animal.yaml, data.verb). Other files may hold any part of speech.
+func partsOfSpeech(file string) []string {
+	name := path.Base(file)
+
+	category, _, _ := strings.Cut(name, ".")
+	if category == "data" {
+		category = strings.TrimPrefix(name, "data.")
+	}
+
+	switch category {
+	case "noun":
+		return []string{string(types.Noun)}
+	case "verb":
+		return []string{string(types.Verb)}
+	case "adj":
+		return []string{string(types.Adjective1), string(types.Adjective2)}
+	case "adv":
+		return []string{string(types.Adverb)}
+	}
+
+	return nil
+}
+
+// mentions - whether the text uses the word, as a whole word and regardless of case.
+func mentions(text, word string) bool {
+	if strings.TrimSpace(word) == "" {
+		return false
+	}
+
+	text, word = strings.ToLower(text), strings.ToLower(word)
+
+	boundary := func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsNumber(r) }
+
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return false
+		}
+
+		start, end := offset+i, offset+i+len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+
+		if (start == 0 || boundary(before)) && (end == len(text) || boundary(after)) {
+			return true
+		}
+
+		offset = start + 1
+	}
+}
+
+// cycles - returns every cycle of the graph once, each starting at its smallest id.
+func cycles(graph map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	var state = map[string]int{}
+	var stack []string
+	var found = map[string][]string{}
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		stack = append(stack, id)
+
+		for _, next := range graph[id] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				var start int
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == next {
+						start = i
+						break
+					}
+				}
+
+				cycle := rotate(append([]string{}, stack[start:]...))
+				found[strings.Join(cycle, " ")] = cycle
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[id] = visited
+	}
+
+	for _, id := range sortedKeys(graph) {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+
+	var result [][]string
+	for _, key := range sortedKeys(found) {
+		result = append(result, found[key])
+	}
+
+	return result
+}
+
+// rotate - moves the smallest id of a cycle to its front, keeping its order.
+func rotate(cycle []string) []string {
+	var smallest int
+	for i, id := range cycle {
+		if id < cycle[smallest] {
+			smallest = i
+		}
+	}
+
+	return append(cycle[smallest:], cycle[:smallest]...)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var keys = make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
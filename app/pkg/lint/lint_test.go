@@ -0,0 +1,202 @@
+package lint_test
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/oleoneto/redic/app/pkg/lint"
+	"github.com/oleoneto/redic/app/pkg/parsers"
+)
+
+func Test_Linter_Run(t *testing.T) {
+	type finding struct {
+		Synset string
+		Check  lint.Check
+	}
+
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  []finding
+	}{
+		{
+			name: "valid",
+			files: fstest.MapFS{
+				"noun.animal.yaml": {Data: []byte(`02084071-n:
+  definition:
+  - a member of the genus Canis
+  hypernym:
+  - 02083346-n
+  members:
+  - dog
+  partOfSpeech: n
+02083346-n:
+  definition:
+  - any of various fissiped mammals with nonretractile claws
+  members:
+  - canine
+  partOfSpeech: n
+`)},
+			},
+			want: []finding{},
+		},
+		{
+			name: "synsets",
+			files: fstest.MapFS{
+				"noun.animal.yaml": {Data: []byte(`02084071-n:
+  definition:
+  - a dog of sorts
+  members:
+  - dog
+  partOfSpeech: v
+02083346-n:
+  definition: []
+  members: []
+  partOfSpeech: n
+02083347-n:
+  definition: []
+  ili: i46360
+  members:
+  - canid
+  partOfSpeech: n
+`)},
+			},
+			want: []finding{
+				{"02083346-n", lint.NoMembers},
+				{"02083346-n", lint.EmptyGloss},
+				{"02083347-n", lint.EmptyGloss},
+				{"02084071-n", lint.PartOfSpeech},
+				{"02084071-n", lint.SelfReference},
+			},
+		},
+		{
+			name: "relations",
+			files: fstest.MapFS{
+				"noun.animal.yaml": {Data: []byte(`02084071-n:
+  definition:
+  - a member of the genus Canis
+  hypernym:
+  - 02083346-n
+  members:
+  - dog
+  partOfSpeech: n
+02083346-n:
+  definition:
+  - any of various fissiped mammals with nonretractile claws
+  hypernym:
+  - 02084071-n
+  similar:
+  - 99999999-n
+  members:
+  - canine
+  partOfSpeech: n
+`)},
+				"noun.plant.yaml": {Data: []byte(`02084071-n:
+  definition:
+  - not a dog
+  members:
+  - hotdog
+  partOfSpeech: n
+`)},
+			},
+			want: []finding{
+				{"02083346-n", lint.DanglingTarget},
+				{"02083346-n", lint.HypernymCycle},
+				{"02084071-n", lint.DuplicateSynset},
+			},
+		},
+		{
+			name: "unreadable",
+			files: fstest.MapFS{
+				"noun.animal.yaml": {Data: []byte("02084071-n: [\n")},
+			},
+			want: []finding{{"", lint.Unreadable}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := parsers.NewAutoParser(
+				func(dir string) ([]fs.DirEntry, error) { return fs.ReadDir(tt.files, dir) },
+				func(name string) (io.ReadCloser, error) { return tt.files.Open(name) },
+			)
+
+			files := parser.LoadFiles(context.Background(), ".")
+
+			report, err := lint.NewLinter(parser).Run(context.Background(), ".", files)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			var got = []finding{}
+			for _, issue := range report.Issues {
+				got = append(got, finding{issue.Synset, issue.Check})
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run() = %v, want %v", got, tt.want)
+			}
+
+			if report.Failed(false) != (report.Errors != 0) {
+				t.Errorf("Failed() = %v with %d errors", report.Failed(false), report.Errors)
+			}
+		})
+	}
+}
+
+func Test_Linter_Run_Partial(t *testing.T) {
+	files := fstest.MapFS{
+		"noun.animal.yaml": {Data: []byte(`02084071-n:
+  definition:
+  - a member of the genus Canis
+  hypernym:
+  - 02083346-n
+  members:
+  - dog
+  partOfSpeech: n
+`)},
+	}
+
+	parser := parsers.NewAutoParser(
+		func(dir string) ([]fs.DirEntry, error) { return fs.ReadDir(files, dir) },
+		func(name string) (io.ReadCloser, error) { return files.Open(name) },
+	)
+
+	tests := []struct {
+		name       string
+		partial    bool
+		references map[string]bool
+		want       []lint.Severity
+	}{
+		{name: "whole lexicon", want: []lint.Severity{lint.Error}},
+		{name: "partial", partial: true, want: []lint.Severity{lint.Warning}},
+		{name: "referenced", partial: true, references: map[string]bool{"02083346-n": true}, want: []lint.Severity{}},
+		{name: "not referenced", partial: true, references: map[string]bool{"02084071-n": true}, want: []lint.Severity{lint.Error}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linter := lint.NewLinter(parser).WithPartial(tt.partial).WithReferences(tt.references)
+
+			report, err := linter.Run(context.Background(), ".", parser.LoadFiles(context.Background(), "."))
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			var got = []lint.Severity{}
+			for _, issue := range report.Issues {
+				if issue.Check == lint.DanglingTarget {
+					got = append(got, issue.Severity)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run() dangling targets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
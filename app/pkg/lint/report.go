@@ -0,0 +1,107 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// Severity - whether an issue keeps a file from being ingested as intended.
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Issue - a problem found in a dictionary file.
+type Issue struct {
+	File     string   `json:"file" yaml:"file"`                         // i.e noun.animal.yaml
+	Synset   string   `json:"synset,omitempty" yaml:"synset,omitempty"` // i.e 02084071-n
+	Check    Check    `json:"check" yaml:"check"`                       // i.e dangling-target
+	Severity Severity `json:"severity" yaml:"severity"`
+	Message  string   `json:"message" yaml:"message"`
+}
+
+func (i Issue) String() string {
+	var location = i.File
+	if i.Synset != "" {
+		location += ": " + i.Synset
+	}
+
+	return fmt.Sprintf("%s: %s: %s (%s)", location, i.Severity, i.Message, i.Check)
+}
+
+// Report - every issue found in a set of dictionary files.
+type Report struct {
+	Files    int     `json:"files" yaml:"files"`
+	Synsets  int     `json:"synsets" yaml:"synsets"`
+	Errors   int     `json:"errors" yaml:"errors"`
+	Warnings int     `json:"warnings" yaml:"warnings"`
+	Issues   []Issue `json:"issues" yaml:"issues"`
+}
+
+func (r *Report) add(issue Issue) {
+	r.Issues = append(r.Issues, issue)
+}
+
+// sort - orders issues by file, synset and check, and counts them by severity.
+func (r *Report) sort() {
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		a, b := r.Issues[i], r.Issues[j]
+
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Synset != b.Synset {
+			return a.Synset < b.Synset
+		}
+		return slices.Index(Checks, a.Check) < slices.Index(Checks, b.Check)
+	})
+
+	r.Errors, r.Warnings = 0, 0
+	for _, issue := range r.Issues {
+		if issue.Severity == Error {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+}
+
+// Failed - returns true if any error was found or, when strict, any warning.
+func (r Report) Failed(strict bool) bool {
+	return r.Errors != 0 || (strict && r.Warnings != 0)
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+
+	for _, issue := range r.Issues {
+		fmt.Fprintln(&b, issue)
+	}
+
+	fmt.Fprintf(&b, "%d files, %d synsets: %d errors, %d warnings\n", r.Files, r.Synsets, r.Errors, r.Warnings)
+
+	return b.String()
+}
+
+func (r Report) TableWriter() table.Writer {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleLight)
+	t.SetOutputMirror(nil) // Delegate printing to gout tool
+
+	t.SetTitle("lint")
+	t.AppendHeader(table.Row{"file", "synset", "severity", "check", "message"})
+
+	for _, issue := range r.Issues {
+		t.AppendRow(table.Row{issue.File, issue.Synset, issue.Severity, issue.Check, issue.Message})
+	}
+
+	t.AppendFooter(table.Row{r.Files, r.Synsets, "", fmt.Sprintf("%d errors", r.Errors), fmt.Sprintf("%d warnings", r.Warnings)})
+
+	return t
+}
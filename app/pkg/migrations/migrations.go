@@ -0,0 +1,405 @@
+// Package migrations evolves the dictionary schema through versioned, embedded SQL files.
+//
+// Every adapter keeps its own migrations under a directory of its name, one pair of
+// files per migration, named after a 20-digit timestamp and a snake_case name:
+//
+//	sqlite3/20261018120000000000_create_dictionary.up.sql
+//	sqlite3/20261018120000000000_create_dictionary.down.sql
+//
+// Applied versions are recorded in the schema_migrations table. Adapters share the same
+// versions, so a migration only one of them needs is a no-op script for the others.
+//
+// Statements that follow a line naming the tables they need only run when every one
+// of those tables exists, so that a down script does not fail on a schema its up script
+// never built:
+//
+//	DROP TABLE IF EXISTS redic_;
+//	-- requires: associations, words
+//	CREATE TRIGGER associations_index AFTER INSERT ON associations ...
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+	dbsql "github.com/oleoneto/redic/app/pkg/repositories/sql"
+)
+
+//go:embed sqlite3/*.sql postgresql/*.sql
+var files embed.FS
+
+var (
+	ErrOutdated = errors.New("database schema is outdated")
+	ErrUnknown  = errors.New("database schema is newer than this build")
+	ErrNotFound = errors.New("migration not found")
+)
+
+var filePattern = regexp.MustCompile(`^(\d{20})_([a-zA-Z]+(?:_?[a-zA-Z])*)\.(up|down)\.sql$`)
+
+var requiresPattern = regexp.MustCompile(`(?m)^-- requires: (.+)$`)
+
+// Migration - a change to the schema along with the means to revert it.
+type Migration struct {
+	Version string `json:"version" yaml:"version"` // i.e 20261018120000000000
+	Name    string `json:"name" yaml:"name"`       // i.e create_dictionary
+	Up      string `json:"-" yaml:"-"`
+	Down    string `json:"-" yaml:"-"`
+}
+
+func (m Migration) String() string { return m.Version + "_" + m.Name }
+
+// Load - returns the migrations of an adapter, oldest first.
+func Load(adapter protocols.SQLAdapter) ([]Migration, error) {
+	// Both SQLite drivers share one schema
+	if adapter == protocols.PureSQLiteAdapter {
+		adapter = protocols.SQLite3Adapter
+	}
+
+	entries, err := fs.ReadDir(files, string(adapter))
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for adapter %q", adapter)
+	}
+
+	var migrations = map[string]*Migration{}
+
+	for _, entry := range entries {
+		matches := filePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("%v: unexpected migration file %s", helpers.GetCurrentFuncName(), entry.Name())
+		}
+
+		version, name, direction := matches[1], matches[2], matches[3]
+
+		m, ok := migrations[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			migrations[version] = m
+		}
+
+		if m.Name != name {
+			return nil, fmt.Errorf("%v: migration %s is named both %s and %s", helpers.GetCurrentFuncName(), version, m.Name, name)
+		}
+
+		b, err := files.ReadFile(path.Join(string(adapter), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if direction == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	var result []Migration
+	for _, m := range migrations {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("%v: migration %s is missing its up or down file", helpers.GetCurrentFuncName(), m)
+		}
+
+		result = append(result, *m)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+
+	return result, nil
+}
+
+// Migrator applies and reverts the migrations of one database.
+type Migrator struct {
+	db         protocols.SqlBackend
+	dialect    dbsql.Dialect
+	migrations []Migration
+}
+
+func NewMigrator(db protocols.SqlBackend, adapter protocols.SQLAdapter) (*Migrator, error) {
+	migrations, err := Load(adapter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: dbsql.DialectOf(adapter), migrations: migrations}, nil
+}
+
+// Find - returns the migration of the given version or name.
+func (m *Migrator) Find(versionOrName string) (Migration, error) {
+	for _, migration := range m.migrations {
+		if migration.Version == versionOrName || migration.Name == versionOrName {
+			return migration, nil
+		}
+	}
+
+	return Migration{}, fmt.Errorf("%w: %s", ErrNotFound, versionOrName)
+}
+
+// Status - returns every migration, known or applied, and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+
+	var status Status
+
+	for _, migration := range m.migrations {
+		entry := Entry{Migration: migration}
+
+		if at, ok := applied[migration.Version]; ok {
+			entry.AppliedAt = &at.at
+			delete(applied, migration.Version)
+		}
+
+		status.Migrations = append(status.Migrations, entry)
+	}
+
+	// Versions recorded by a newer build, which this one cannot revert
+	for version, at := range applied {
+		status.Migrations = append(status.Migrations, Entry{
+			Migration: Migration{Version: version, Name: at.name},
+			AppliedAt: &at.at,
+			Unknown:   true,
+		})
+	}
+
+	sort.Slice(status.Migrations, func(i, j int) bool { return status.Migrations[i].Version < status.Migrations[j].Version })
+
+	return status, nil
+}
+
+// Check - returns ErrOutdated if any migration is pending, and ErrUnknown if the
+// database has been migrated by a newer build.
+func (m *Migrator) Check(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if unknown := status.Unknown(); len(unknown) != 0 {
+		return fmt.Errorf("%w: %s", ErrUnknown, strings.Join(helpers.Map(unknown, func(_ int, e Entry) string { return e.String() }), ", "))
+	}
+
+	if pending := status.Pending(); len(pending) != 0 {
+		return fmt.Errorf("%w: %d pending migrations, run `redic migrate up`", ErrOutdated, len(pending))
+	}
+
+	return nil
+}
+
+// Up - applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down - reverts the last n applied migrations.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	applied := status.Applied()
+
+	for i := len(applied) - 1; i >= 0 && n > 0; i, n = i-1, n-1 {
+		if applied[i].Unknown {
+			return fmt.Errorf("%w: cannot revert %s", ErrUnknown, applied[i])
+		}
+
+		if err := m.revert(ctx, applied[i].Migration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// To - applies every migration up to and including the given version, and reverts
+// those that come after it.
+func (m *Migrator) To(ctx context.Context, version string) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := len(status.Migrations) - 1; i >= 0; i-- {
+		entry := status.Migrations[i]
+		if entry.Version <= version || entry.AppliedAt == nil {
+			continue
+		}
+
+		if entry.Unknown {
+			return fmt.Errorf("%w: cannot revert %s", ErrUnknown, entry)
+		}
+
+		if err := m.revert(ctx, entry.Migration); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range status.Migrations {
+		if entry.Version > version || entry.AppliedAt != nil {
+			continue
+		}
+
+		if err := m.apply(ctx, entry.Migration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reset - reverts every applied migration, newest first, and applies them all again.
+// A database with nothing recorded has the down script of the first migration run anyway:
+// it only drops objects if they exist, so this also clears databases initialized before
+// migrations were tracked.
+func (m *Migrator) Reset(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		if err := m.revert(ctx, m.migrations[0]); err != nil {
+			return err
+		}
+
+		return m.Up(ctx)
+	}
+
+	for version, at := range applied {
+		if _, err := m.Find(version); err != nil {
+			return fmt.Errorf("%w: cannot revert %s_%s", ErrUnknown, version, at.name)
+		}
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[m.migrations[i].Version]; !ok {
+			continue
+		}
+
+		if err := m.revert(ctx, m.migrations[i]); err != nil {
+			return err
+		}
+	}
+
+	return m.Up(ctx)
+}
+
+type record struct {
+	name string
+	at   time.Time
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[string]record, error) {
+	if _, err := m.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, name, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applied = map[string]record{}
+
+	for rows.Next() {
+		var version string
+		var r record
+
+		if err := rows.Scan(&version, &r.name, &r.at); err != nil {
+			return nil, err
+		}
+
+		applied[version] = r
+	}
+
+	return applied, rows.Err()
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	query := fmt.Sprintf(`INSERT INTO schema_migrations (version, name) VALUES (%s, %s)`, m.dialect.Placeholder(1), m.dialect.Placeholder(2))
+	return m.run(ctx, migration, migration.Up, query, migration.Version, migration.Name)
+}
+
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	query := fmt.Sprintf(`DELETE FROM schema_migrations WHERE version = %s`, m.dialect.Placeholder(1))
+	return m.run(ctx, migration, migration.Down, query, migration.Version)
+}
+
+// run - executes a migration and records it in a single transaction.
+func (m *Migrator) run(ctx context.Context, migration Migration, script string, record string, args ...any) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var guarded string
+	var tables []string
+
+	if loc := requiresPattern.FindStringSubmatchIndex(script); loc != nil {
+		tables = strings.Split(script[loc[2]:loc[3]], ",")
+		script, guarded = script[:loc[0]], script[loc[1]:]
+	}
+
+	if strings.TrimSpace(script) != "" {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			return fmt.Errorf("%s: %w", migration, err)
+		}
+	}
+
+	if guarded != "" {
+		exist, err := m.tablesExist(ctx, tx, tables)
+		if err != nil {
+			return fmt.Errorf("%s: %w", migration, err)
+		}
+
+		if exist {
+			if _, err := tx.ExecContext(ctx, guarded); err != nil {
+				return fmt.Errorf("%s: %w", migration, err)
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("%s: %w", migration, err)
+	}
+
+	return tx.Commit()
+}
+
+// tablesExist - whether every one of the tables exists.
+func (m *Migrator) tablesExist(ctx context.Context, tx *sql.Tx, tables []string) (bool, error) {
+	var q = dbsql.NewQuery(m.dialect)
+	var names = q.BindAll(helpers.Map(tables, func(_ int, table string) any { return strings.TrimSpace(table) })...)
+
+	query := `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN (%s)`
+	if m.dialect.Adapter() == protocols.PostgreSQLAdapter {
+		query = `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name IN (%s)`
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(query, names), q.Args()...).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count == len(tables), nil
+}
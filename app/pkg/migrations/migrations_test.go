@@ -0,0 +1,204 @@
+package migrations_test
+
+import (
+	"context"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/pkg/migrations"
+	dbsql "github.com/oleoneto/redic/app/pkg/repositories/sql"
+	"github.com/oleoneto/redic/cmd/cli/core"
+)
+
+func Test_Load(t *testing.T) {
+	var versions = map[protocols.SQLAdapter][]string{}
+
+	for _, adapter := range []protocols.SQLAdapter{protocols.SQLite3Adapter, protocols.PureSQLiteAdapter, protocols.PostgreSQLAdapter} {
+		t.Run(string(adapter), func(t *testing.T) {
+			loaded, err := migrations.Load(adapter)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if len(loaded) == 0 {
+				t.Fatalf("Load() returned no migrations")
+			}
+
+			for i, m := range loaded {
+				if i > 0 && loaded[i-1].Version >= m.Version {
+					t.Errorf("Load() returned %s after %s", m, loaded[i-1])
+				}
+
+				if v, err := core.ParseVersionArgs(m.Version); err != nil || v.Type != "version" {
+					t.Errorf("Load() version %q is not a valid version", m.Version)
+				}
+
+				if n, err := core.ParseVersionArgs(m.Name); err != nil || n.Type != "name" {
+					t.Errorf("Load() name %q is not a valid name", m.Name)
+				}
+
+				if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+					t.Errorf("Load() %s has an empty script", m)
+				}
+
+				versions[adapter] = append(versions[adapter], m.Version)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(versions[protocols.SQLite3Adapter], versions[protocols.PureSQLiteAdapter]) {
+		t.Errorf("Load() SQLite drivers disagree on versions: %v and %v", versions[protocols.SQLite3Adapter], versions[protocols.PureSQLiteAdapter])
+	}
+
+	if !reflect.DeepEqual(versions[protocols.SQLite3Adapter], versions[protocols.PostgreSQLAdapter]) {
+		t.Errorf("Load() adapters disagree on versions: %v and %v", versions[protocols.SQLite3Adapter], versions[protocols.PostgreSQLAdapter])
+	}
+
+	if _, err := migrations.Load("turso"); err == nil {
+		t.Errorf("Load(turso) error = nil, want an error")
+	}
+}
+
+func Test_Status(t *testing.T) {
+	status := migrations.Status{Migrations: []migrations.Entry{
+		{Migration: migrations.Migration{Version: "20261018120000000000", Name: "create_dictionary"}, AppliedAt: new(time.Time)},
+		{Migration: migrations.Migration{Version: "20261019120000000000", Name: "add_index"}},
+	}}
+
+	if got := status.Current(); got != "20261018120000000000" {
+		t.Errorf("Current() = %q, want 20261018120000000000", got)
+	}
+
+	if got := len(status.Pending()); got != 1 {
+		t.Errorf("Pending() returned %d migrations, want 1", got)
+	}
+}
+
+// databases - an empty database for each adapter the tests can reach.
+// The PostgreSQL database at REDIC_TEST_DATABASE_URL is wiped.
+func databases(t *testing.T) []protocols.DBConnectOptions {
+	var options = []protocols.DBConnectOptions{
+		{Adapter: protocols.SQLite3Adapter, Filename: filepath.Join(t.TempDir(), "redic.db")},
+		{Adapter: protocols.PureSQLiteAdapter, Filename: filepath.Join(t.TempDir(), "redic.db")},
+	}
+
+	if dsn := os.Getenv("REDIC_TEST_DATABASE_URL"); dsn != "" {
+		options = append(options, protocols.DBConnectOptions{Adapter: protocols.PostgreSQLAdapter, DSN: dsn})
+	}
+
+	return options
+}
+
+// connect - opens the database, skipping the test where the adapter cannot run the migrations.
+func connect(t *testing.T, option protocols.DBConnectOptions) (protocols.SqlBackend, *migrations.Migrator) {
+	db, err := dbsql.ConnectDatabase(option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.(io.Closer).Close() })
+
+	/* The CGO driver needs the fts5 build tag, i.e go test -tags "fts5" */
+	if option.Adapter == protocols.SQLite3Adapter {
+		var fts5 bool
+		if err := db.QueryRowContext(context.Background(), `SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&fts5); err != nil {
+			t.Skip(err)
+		}
+		if !fts5 {
+			t.Skip("sqlite3 is built without FTS5")
+		}
+	}
+
+	migrator, err := migrations.NewMigrator(db, option.Adapter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db, migrator
+}
+
+func Test_Migrator_Reset(t *testing.T) {
+	ctx := context.Background()
+
+	for _, option := range databases(t) {
+		t.Run(string(option.Adapter), func(t *testing.T) {
+			db, migrator := connect(t, option)
+
+			/* Once on an empty database, then on the database it left */
+			for i := range 2 {
+				if err := migrator.Reset(ctx); err != nil {
+					t.Fatalf("Reset() #%d error = %v", i+1, err)
+				}
+
+				if err := migrator.Check(ctx); err != nil {
+					t.Fatalf("Reset() #%d left the schema outdated: %v", i+1, err)
+				}
+
+				if _, err := db.ExecContext(ctx, `INSERT INTO sources (name, hash) VALUES ('noun.animal.yaml', 'a')`); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := migrator.Reset(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			var sources int
+			if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sources`).Scan(&sources); err != nil || sources != 0 {
+				t.Errorf("Reset() kept %d sources (%v), want none", sources, err)
+			}
+
+			if err := migrator.Down(ctx, math.MaxInt); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func Test_Migrator_Down_MissingTables(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		version string
+	}{
+		{name: "sync search index", version: "20261018140000000000"},
+		{name: "weight search fields", version: "20261018150000000000"},
+	}
+
+	for _, tt := range tests {
+		for _, option := range databases(t) {
+			t.Run(tt.name+"/"+string(option.Adapter), func(t *testing.T) {
+				db, migrator := connect(t, option)
+
+				if err := migrator.To(ctx, tt.version); err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { migrator.Down(ctx, math.MaxInt) })
+
+				drop := `DROP TABLE associations`
+				if option.Adapter == protocols.PostgreSQLAdapter {
+					drop += ` CASCADE`
+				}
+
+				if _, err := db.ExecContext(ctx, drop); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := migrator.Down(ctx, math.MaxInt); err != nil {
+					t.Fatalf("Down() error = %v", err)
+				}
+
+				if status, err := migrator.Status(ctx); err != nil || len(status.Applied()) != 0 {
+					t.Errorf("Down() left %d migrations applied (%v)", len(status.Applied()), err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,8 @@
+DROP VIEW IF EXISTS dictionary;
+DROP TABLE IF EXISTS examples;
+DROP TABLE IF EXISTS relations;
+DROP TABLE IF EXISTS associations;
+DROP TABLE IF EXISTS synsets;
+DROP TABLE IF EXISTS explanations;
+DROP TABLE IF EXISTS words;
+DROP TABLE IF EXISTS sources;
@@ -0,0 +1,94 @@
+-- The schema as it stood before migrations were tracked. Objects are created only if
+-- missing, so that databases initialized by earlier releases adopt this migration.
+
+-- Words are kept apart by their BCP-47 language (i.e en, pt-BR).
+CREATE TABLE IF NOT EXISTS words (
+	id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	text TEXT NOT NULL,
+	part_of_speech TEXT NOT NULL,
+	language TEXT NOT NULL DEFAULT 'en',
+	UNIQUE (text, part_of_speech, language)
+);
+
+CREATE TABLE IF NOT EXISTS explanations (
+	id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	text TEXT NOT NULL UNIQUE
+);
+
+-- A set of words sharing one meaning, keyed by its WordNet offset (i.e 00003552-s).
+-- Entries that do not come from WordNet use a local id (i.e x3f1c2a9b-n).
+-- Synsets of other languages are prefixed with their language (i.e pt:00003552-s)
+-- and linked to their English counterparts through the interlingual index (ili).
+CREATE TABLE IF NOT EXISTS synsets (
+	id TEXT PRIMARY KEY,
+	ili TEXT,
+	part_of_speech TEXT NOT NULL,
+	language TEXT NOT NULL DEFAULT 'en',
+	explanation_id INTEGER NOT NULL REFERENCES explanations (id),
+	source TEXT,
+	checksum TEXT,
+	lexfile TEXT
+);
+
+CREATE INDEX IF NOT EXISTS synsets_ili ON synsets (ili);
+CREATE INDEX IF NOT EXISTS synsets_lexfile ON synsets (lexfile);
+CREATE INDEX IF NOT EXISTS synsets_source ON synsets (source);
+
+-- Files ingested so far, with the hash of their contents at the time (i.e noun.animal.yaml).
+-- Files whose hash is unchanged are skipped when the dictionary is repopulated.
+CREATE TABLE IF NOT EXISTS sources (
+	name TEXT PRIMARY KEY,
+	hash TEXT NOT NULL,
+	synsets INTEGER NOT NULL DEFAULT 0,
+	imported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Members of a synset, in WordNet order.
+CREATE TABLE IF NOT EXISTS associations (
+	word_id INTEGER NOT NULL REFERENCES words (id) ON DELETE CASCADE,
+	synset_id TEXT NOT NULL REFERENCES synsets (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL DEFAULT 0,
+	explicit BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (word_id, synset_id)
+);
+
+CREATE INDEX IF NOT EXISTS associations_synset_id ON associations (synset_id);
+
+-- Sentences illustrating a synset. Quotations carry their source (i.e Winston Churchill).
+CREATE TABLE IF NOT EXISTS examples (
+	synset_id TEXT NOT NULL REFERENCES synsets (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	text TEXT NOT NULL,
+	source TEXT,
+	PRIMARY KEY (synset_id, position)
+);
+
+-- Typed edges between synsets (i.e 00003552-s similar 00003356-a).
+-- Targets may live in another lexicographer file, so they are not constrained.
+CREATE TABLE IF NOT EXISTS relations (
+	source_id TEXT NOT NULL,
+	target_id TEXT NOT NULL,
+	relation_type TEXT NOT NULL,
+	PRIMARY KEY (source_id, target_id, relation_type)
+);
+
+CREATE INDEX IF NOT EXISTS relations_target_id ON relations (target_id);
+
+CREATE OR REPLACE VIEW dictionary AS
+SELECT
+	w.id,
+	w.text AS word,
+	w.part_of_speech,
+	e.text AS explanation,
+	e.id AS explanation_id,
+	s.id AS synset_id,
+	s.ili,
+	s.language,
+	s.lexfile,
+	a.position,
+	a.explicit
+FROM
+	words w
+	JOIN associations a ON a.word_id = w.id
+	JOIN synsets s ON s.id = a.synset_id
+	JOIN explanations e ON e.id = s.explanation_id;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS associations_document;
+
+ALTER TABLE IF EXISTS associations DROP COLUMN IF EXISTS document;
@@ -0,0 +1,18 @@
+-- Words are searched along with their definition through a tsvector per member of a synset,
+-- the counterpart of SQLite's redic_ table. The simple configuration neither stems nor drops
+-- stop words, as FTS5's default tokenizer does not; inflections are resolved by the query.
+ALTER TABLE associations ADD COLUMN IF NOT EXISTS document TSVECTOR;
+
+UPDATE associations a
+SET
+	document = to_tsvector('simple', w.text) || to_tsvector('simple', e.text)
+FROM
+	words w,
+	synsets s,
+	explanations e
+WHERE
+	w.id = a.word_id
+	AND s.id = a.synset_id
+	AND e.id = s.explanation_id;
+
+CREATE INDEX IF NOT EXISTS associations_document ON associations USING GIN (document);
@@ -0,0 +1,3 @@
+-- PostgreSQL writes the document of an association along with it, so the index is
+-- always in step already.
+SELECT 1;
@@ -0,0 +1,12 @@
+-- requires: words, explanations, synsets, associations
+UPDATE associations a
+SET
+	document = to_tsvector('simple', w.text) || to_tsvector('simple', e.text)
+FROM
+	words w,
+	synsets s,
+	explanations e
+WHERE
+	w.id = a.word_id
+	AND s.id = a.synset_id
+	AND e.id = s.explanation_id;
@@ -0,0 +1,15 @@
+-- Documents gain the examples of their synset, and label each field with a weight class
+-- (word A, definition B, examples C) that searches weigh apart.
+UPDATE associations a
+SET
+	document = setweight(to_tsvector('simple', w.text), 'A')
+		|| setweight(to_tsvector('simple', e.text), 'B')
+		|| setweight(to_tsvector('simple', COALESCE((SELECT string_agg(x.text, E'\n' ORDER BY x.position) FROM examples x WHERE x.synset_id = a.synset_id), '')), 'C')
+FROM
+	words w,
+	synsets s,
+	explanations e
+WHERE
+	w.id = a.word_id
+	AND s.id = a.synset_id
+	AND e.id = s.explanation_id;
@@ -0,0 +1,2 @@
+-- Nothing to undo: the up migration only keeps SQLite's versions in step with PostgreSQL's.
+SELECT 1;
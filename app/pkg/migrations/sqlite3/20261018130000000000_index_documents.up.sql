@@ -0,0 +1,6 @@
+-- Nothing to do: PostgreSQL adds the tsvector document it searches by here, while SQLite
+-- searches the redic_ FTS5 table created along with the dictionary.
+--
+-- The migration is kept so that both adapters share the same versions, which lets
+-- `redic migrate status` and the schema check read any database the same way.
+SELECT 1;
@@ -0,0 +1,57 @@
+DROP TRIGGER IF EXISTS associations_index;
+DROP TRIGGER IF EXISTS associations_unindex;
+DROP TRIGGER IF EXISTS synsets_unindex;
+DROP TRIGGER IF EXISTS synsets_reexplain;
+DROP TRIGGER IF EXISTS synsets_reindex;
+DROP TRIGGER IF EXISTS words_rename;
+DROP TRIGGER IF EXISTS words_reindex;
+DROP TRIGGER IF EXISTS explanations_rewrite;
+DROP TRIGGER IF EXISTS explanations_reindex;
+
+DROP TABLE IF EXISTS redic_;
+DROP VIEW IF EXISTS redic_entries;
+DROP VIEW IF EXISTS dictionary;
+
+-- The dictionary is rebuilt as it was, unless its tables are gone already.
+-- requires: words, explanations, synsets, associations
+DROP TABLE IF EXISTS associations_;
+
+CREATE TABLE associations_ (
+	word_id INTEGER NOT NULL REFERENCES words (id) ON DELETE CASCADE,
+	synset_id TEXT NOT NULL REFERENCES synsets (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL DEFAULT 0,
+	explicit BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (word_id, synset_id)
+);
+
+INSERT INTO associations_ (word_id, synset_id, position, explicit)
+SELECT word_id, synset_id, position, explicit FROM associations ORDER BY id;
+
+DROP TABLE IF EXISTS associations;
+
+ALTER TABLE associations_ RENAME TO associations;
+
+CREATE INDEX associations_synset_id ON associations (synset_id);
+
+CREATE VIEW dictionary AS
+SELECT
+	w.id,
+	w.text AS word,
+	w.part_of_speech,
+	e.text AS explanation,
+	e.id AS explanation_id,
+	s.id AS synset_id,
+	s.ili,
+	s.language,
+	s.lexfile,
+	a.position,
+	a.explicit
+FROM
+	words w
+	JOIN associations a ON a.word_id = w.id
+	JOIN synsets s ON s.id = a.synset_id
+	JOIN explanations e ON e.id = s.explanation_id;
+
+CREATE VIRTUAL TABLE redic_ USING fts5 (word_id UNINDEXED, word, definition, synset_id UNINDEXED);
+
+INSERT INTO redic_ (word_id, word, definition, synset_id) SELECT id, word, explanation, synset_id FROM dictionary;
@@ -0,0 +1,131 @@
+-- redic_ becomes an external-content FTS5 table: it keeps only the index and reads words
+-- and definitions from the redic_entries view, through the id of their association.
+-- Triggers keep the index in step with every write, so entries are searchable as soon as
+-- they are added.
+--
+-- VACUUM may renumber implicit rowids, so associations get an id of their own first.
+DROP VIEW IF EXISTS dictionary;
+
+CREATE TABLE associations_ (
+	id INTEGER PRIMARY KEY,
+	word_id INTEGER NOT NULL REFERENCES words (id) ON DELETE CASCADE,
+	synset_id TEXT NOT NULL REFERENCES synsets (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL DEFAULT 0,
+	explicit BOOLEAN NOT NULL DEFAULT FALSE,
+	UNIQUE (word_id, synset_id)
+);
+
+INSERT INTO associations_ (word_id, synset_id, position, explicit)
+SELECT word_id, synset_id, position, explicit FROM associations ORDER BY rowid;
+
+DROP TABLE associations;
+
+ALTER TABLE associations_ RENAME TO associations;
+
+CREATE INDEX associations_synset_id ON associations (synset_id);
+
+CREATE VIEW dictionary AS
+SELECT
+	w.id,
+	w.text AS word,
+	w.part_of_speech,
+	e.text AS explanation,
+	e.id AS explanation_id,
+	s.id AS synset_id,
+	s.ili,
+	s.language,
+	s.lexfile,
+	a.position,
+	a.explicit
+FROM
+	words w
+	JOIN associations a ON a.word_id = w.id
+	JOIN synsets s ON s.id = a.synset_id
+	JOIN explanations e ON e.id = s.explanation_id;
+
+CREATE VIEW redic_entries AS
+SELECT
+	a.id,
+	w.text AS word,
+	e.text AS definition
+FROM
+	associations a
+	JOIN words w ON w.id = a.word_id
+	JOIN synsets s ON s.id = a.synset_id
+	JOIN explanations e ON e.id = s.explanation_id;
+
+DROP TABLE IF EXISTS redic_;
+
+CREATE VIRTUAL TABLE redic_ USING fts5 (word, definition, content = 'redic_entries', content_rowid = 'id');
+
+INSERT INTO redic_ (redic_) VALUES ('rebuild');
+
+-- Entries are removed with the values they were indexed with, so removals run while the
+-- rows they come from still exist. A synset is gone by the time its associations cascade,
+-- which leaves those to the synset's own trigger.
+CREATE TRIGGER associations_index AFTER INSERT ON associations BEGIN
+	INSERT INTO redic_ (rowid, word, definition)
+	SELECT id, word, definition FROM redic_entries WHERE id = new.id;
+END;
+
+CREATE TRIGGER associations_unindex AFTER DELETE ON associations BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition)
+	SELECT 'delete', old.id, w.text, e.text
+	FROM words w, synsets s, explanations e
+	WHERE w.id = old.word_id AND s.id = old.synset_id AND e.id = s.explanation_id;
+END;
+
+CREATE TRIGGER synsets_unindex BEFORE DELETE ON synsets BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition)
+	SELECT 'delete', a.id, w.text, e.text
+	FROM associations a, words w, explanations e
+	WHERE a.synset_id = old.id AND w.id = a.word_id AND e.id = old.explanation_id;
+END;
+
+CREATE TRIGGER synsets_reexplain BEFORE UPDATE OF explanation_id ON synsets
+WHEN old.explanation_id <> new.explanation_id BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition)
+	SELECT 'delete', a.id, w.text, e.text
+	FROM associations a, words w, explanations e
+	WHERE a.synset_id = old.id AND w.id = a.word_id AND e.id = old.explanation_id;
+END;
+
+CREATE TRIGGER synsets_reindex AFTER UPDATE OF explanation_id ON synsets
+WHEN old.explanation_id <> new.explanation_id BEGIN
+	INSERT INTO redic_ (rowid, word, definition)
+	SELECT a.id, w.text, e.text
+	FROM associations a, words w, explanations e
+	WHERE a.synset_id = new.id AND w.id = a.word_id AND e.id = new.explanation_id;
+END;
+
+CREATE TRIGGER words_rename BEFORE UPDATE OF text ON words
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition)
+	SELECT 'delete', a.id, old.text, e.text
+	FROM associations a, synsets s, explanations e
+	WHERE a.word_id = old.id AND s.id = a.synset_id AND e.id = s.explanation_id;
+END;
+
+CREATE TRIGGER words_reindex AFTER UPDATE OF text ON words
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (rowid, word, definition)
+	SELECT a.id, new.text, e.text
+	FROM associations a, synsets s, explanations e
+	WHERE a.word_id = new.id AND s.id = a.synset_id AND e.id = s.explanation_id;
+END;
+
+CREATE TRIGGER explanations_rewrite BEFORE UPDATE OF text ON explanations
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition)
+	SELECT 'delete', a.id, w.text, old.text
+	FROM synsets s, associations a, words w
+	WHERE s.explanation_id = old.id AND a.synset_id = s.id AND w.id = a.word_id;
+END;
+
+CREATE TRIGGER explanations_reindex AFTER UPDATE OF text ON explanations
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (rowid, word, definition)
+	SELECT a.id, w.text, new.text
+	FROM synsets s, associations a, words w
+	WHERE s.explanation_id = new.id AND a.synset_id = s.id AND w.id = a.word_id;
+END;
@@ -0,0 +1,108 @@
+DROP TRIGGER IF EXISTS associations_index;
+DROP TRIGGER IF EXISTS associations_unindex;
+DROP TRIGGER IF EXISTS synsets_unindex;
+DROP TRIGGER IF EXISTS synsets_reexplain;
+DROP TRIGGER IF EXISTS synsets_reindex;
+DROP TRIGGER IF EXISTS words_unindex;
+DROP TRIGGER IF EXISTS words_rename;
+DROP TRIGGER IF EXISTS words_reindex;
+DROP TRIGGER IF EXISTS explanations_rewrite;
+DROP TRIGGER IF EXISTS explanations_reindex;
+DROP TRIGGER IF EXISTS examples_unindex;
+DROP TRIGGER IF EXISTS examples_index;
+DROP TRIGGER IF EXISTS examples_unlist;
+DROP TRIGGER IF EXISTS examples_relist;
+DROP TRIGGER IF EXISTS examples_rewrite;
+DROP TRIGGER IF EXISTS examples_reindex;
+
+DROP TABLE IF EXISTS redic_;
+DROP VIEW IF EXISTS redic_entries;
+
+-- The unweighted index is rebuilt, unless the dictionary is gone already.
+-- requires: words, explanations, synsets, associations
+CREATE VIEW redic_entries AS
+SELECT
+	a.id,
+	w.text AS word,
+	e.text AS definition
+FROM
+	associations a
+	JOIN words w ON w.id = a.word_id
+	JOIN synsets s ON s.id = a.synset_id
+	JOIN explanations e ON e.id = s.explanation_id;
+
+DROP TABLE IF EXISTS redic_;
+
+CREATE VIRTUAL TABLE redic_ USING fts5 (word, definition, content = 'redic_entries', content_rowid = 'id');
+
+INSERT INTO redic_ (redic_) VALUES ('rebuild');
+
+-- Entries are removed with the values they were indexed with, so removals run while the
+-- rows they come from still exist. A synset is gone by the time its associations cascade,
+-- which leaves those to the synset's own trigger.
+CREATE TRIGGER associations_index AFTER INSERT ON associations BEGIN
+	INSERT INTO redic_ (rowid, word, definition)
+	SELECT id, word, definition FROM redic_entries WHERE id = new.id;
+END;
+
+CREATE TRIGGER associations_unindex AFTER DELETE ON associations BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition)
+	SELECT 'delete', old.id, w.text, e.text
+	FROM words w, synsets s, explanations e
+	WHERE w.id = old.word_id AND s.id = old.synset_id AND e.id = s.explanation_id;
+END;
+
+CREATE TRIGGER synsets_unindex BEFORE DELETE ON synsets BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition)
+	SELECT 'delete', a.id, w.text, e.text
+	FROM associations a, words w, explanations e
+	WHERE a.synset_id = old.id AND w.id = a.word_id AND e.id = old.explanation_id;
+END;
+
+CREATE TRIGGER synsets_reexplain BEFORE UPDATE OF explanation_id ON synsets
+WHEN old.explanation_id <> new.explanation_id BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition)
+	SELECT 'delete', a.id, w.text, e.text
+	FROM associations a, words w, explanations e
+	WHERE a.synset_id = old.id AND w.id = a.word_id AND e.id = old.explanation_id;
+END;
+
+CREATE TRIGGER synsets_reindex AFTER UPDATE OF explanation_id ON synsets
+WHEN old.explanation_id <> new.explanation_id BEGIN
+	INSERT INTO redic_ (rowid, word, definition)
+	SELECT a.id, w.text, e.text
+	FROM associations a, words w, explanations e
+	WHERE a.synset_id = new.id AND w.id = a.word_id AND e.id = new.explanation_id;
+END;
+
+CREATE TRIGGER words_rename BEFORE UPDATE OF text ON words
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition)
+	SELECT 'delete', a.id, old.text, e.text
+	FROM associations a, synsets s, explanations e
+	WHERE a.word_id = old.id AND s.id = a.synset_id AND e.id = s.explanation_id;
+END;
+
+CREATE TRIGGER words_reindex AFTER UPDATE OF text ON words
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (rowid, word, definition)
+	SELECT a.id, new.text, e.text
+	FROM associations a, synsets s, explanations e
+	WHERE a.word_id = new.id AND s.id = a.synset_id AND e.id = s.explanation_id;
+END;
+
+CREATE TRIGGER explanations_rewrite BEFORE UPDATE OF text ON explanations
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition)
+	SELECT 'delete', a.id, w.text, old.text
+	FROM synsets s, associations a, words w
+	WHERE s.explanation_id = old.id AND a.synset_id = s.id AND w.id = a.word_id;
+END;
+
+CREATE TRIGGER explanations_reindex AFTER UPDATE OF text ON explanations
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (rowid, word, definition)
+	SELECT a.id, w.text, new.text
+	FROM synsets s, associations a, words w
+	WHERE s.explanation_id = new.id AND a.synset_id = s.id AND w.id = a.word_id;
+END;
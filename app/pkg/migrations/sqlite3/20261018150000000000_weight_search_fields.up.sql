@@ -0,0 +1,127 @@
+-- Examples are indexed along with words and definitions, each in a column of its own, so
+-- that searches can weigh matches in each apart.
+--
+-- Every trigger now reads what it indexes or removes from the redic_entries view: before
+-- a write, the view still holds what was indexed, and after it, what is to be. Deleting a
+-- synset or a word cascades to rows the view no longer joins, so their own triggers remove
+-- their entries beforehand.
+DROP TRIGGER IF EXISTS associations_index;
+DROP TRIGGER IF EXISTS associations_unindex;
+DROP TRIGGER IF EXISTS synsets_unindex;
+DROP TRIGGER IF EXISTS synsets_reexplain;
+DROP TRIGGER IF EXISTS synsets_reindex;
+DROP TRIGGER IF EXISTS words_rename;
+DROP TRIGGER IF EXISTS words_reindex;
+DROP TRIGGER IF EXISTS explanations_rewrite;
+DROP TRIGGER IF EXISTS explanations_reindex;
+
+DROP TABLE IF EXISTS redic_;
+DROP VIEW IF EXISTS redic_entries;
+
+CREATE VIEW redic_entries AS
+SELECT
+	a.id,
+	a.word_id,
+	a.synset_id,
+	w.text AS word,
+	e.text AS definition,
+	COALESCE((
+		SELECT group_concat(text, char(10)) FROM (SELECT x.text FROM examples x WHERE x.synset_id = a.synset_id ORDER BY x.position)
+	), '') AS examples
+FROM
+	associations a
+	JOIN words w ON w.id = a.word_id
+	JOIN synsets s ON s.id = a.synset_id
+	JOIN explanations e ON e.id = s.explanation_id;
+
+CREATE VIRTUAL TABLE redic_ USING fts5 (word, definition, examples, content = 'redic_entries', content_rowid = 'id');
+
+INSERT INTO redic_ (redic_) VALUES ('rebuild');
+
+CREATE TRIGGER associations_index AFTER INSERT ON associations BEGIN
+	INSERT INTO redic_ (rowid, word, definition, examples)
+	SELECT id, word, definition, examples FROM redic_entries WHERE id = new.id;
+END;
+
+CREATE TRIGGER associations_unindex BEFORE DELETE ON associations BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition, examples)
+	SELECT 'delete', id, word, definition, examples FROM redic_entries WHERE id = old.id;
+END;
+
+CREATE TRIGGER synsets_unindex BEFORE DELETE ON synsets BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition, examples)
+	SELECT 'delete', id, word, definition, examples FROM redic_entries WHERE synset_id = old.id;
+END;
+
+CREATE TRIGGER synsets_reexplain BEFORE UPDATE OF explanation_id ON synsets
+WHEN old.explanation_id <> new.explanation_id BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition, examples)
+	SELECT 'delete', id, word, definition, examples FROM redic_entries WHERE synset_id = old.id;
+END;
+
+CREATE TRIGGER synsets_reindex AFTER UPDATE OF explanation_id ON synsets
+WHEN old.explanation_id <> new.explanation_id BEGIN
+	INSERT INTO redic_ (rowid, word, definition, examples)
+	SELECT id, word, definition, examples FROM redic_entries WHERE synset_id = new.id;
+END;
+
+CREATE TRIGGER words_unindex BEFORE DELETE ON words BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition, examples)
+	SELECT 'delete', id, word, definition, examples FROM redic_entries WHERE word_id = old.id;
+END;
+
+CREATE TRIGGER words_rename BEFORE UPDATE OF text ON words
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition, examples)
+	SELECT 'delete', id, word, definition, examples FROM redic_entries WHERE word_id = old.id;
+END;
+
+CREATE TRIGGER words_reindex AFTER UPDATE OF text ON words
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (rowid, word, definition, examples)
+	SELECT id, word, definition, examples FROM redic_entries WHERE word_id = new.id;
+END;
+
+CREATE TRIGGER explanations_rewrite BEFORE UPDATE OF text ON explanations
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition, examples)
+	SELECT 'delete', id, word, definition, examples FROM redic_entries
+	WHERE synset_id IN (SELECT id FROM synsets WHERE explanation_id = old.id);
+END;
+
+CREATE TRIGGER explanations_reindex AFTER UPDATE OF text ON explanations
+WHEN old.text <> new.text BEGIN
+	INSERT INTO redic_ (rowid, word, definition, examples)
+	SELECT id, word, definition, examples FROM redic_entries
+	WHERE synset_id IN (SELECT id FROM synsets WHERE explanation_id = new.id);
+END;
+
+CREATE TRIGGER examples_unindex BEFORE INSERT ON examples BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition, examples)
+	SELECT 'delete', id, word, definition, examples FROM redic_entries WHERE synset_id = new.synset_id;
+END;
+
+CREATE TRIGGER examples_index AFTER INSERT ON examples BEGIN
+	INSERT INTO redic_ (rowid, word, definition, examples)
+	SELECT id, word, definition, examples FROM redic_entries WHERE synset_id = new.synset_id;
+END;
+
+CREATE TRIGGER examples_unlist BEFORE DELETE ON examples BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition, examples)
+	SELECT 'delete', id, word, definition, examples FROM redic_entries WHERE synset_id = old.synset_id;
+END;
+
+CREATE TRIGGER examples_relist AFTER DELETE ON examples BEGIN
+	INSERT INTO redic_ (rowid, word, definition, examples)
+	SELECT id, word, definition, examples FROM redic_entries WHERE synset_id = old.synset_id;
+END;
+
+CREATE TRIGGER examples_rewrite BEFORE UPDATE ON examples BEGIN
+	INSERT INTO redic_ (redic_, rowid, word, definition, examples)
+	SELECT 'delete', id, word, definition, examples FROM redic_entries WHERE synset_id IN (old.synset_id, new.synset_id);
+END;
+
+CREATE TRIGGER examples_reindex AFTER UPDATE ON examples BEGIN
+	INSERT INTO redic_ (rowid, word, definition, examples)
+	SELECT id, word, definition, examples FROM redic_entries WHERE synset_id IN (old.synset_id, new.synset_id);
+END;
@@ -0,0 +1,99 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// Entry - a migration and when, if ever, it was applied.
+type Entry struct {
+	Migration `yaml:",inline"`
+	AppliedAt *time.Time `json:"applied_at" yaml:"applied_at"`
+	Unknown   bool       `json:"unknown,omitempty" yaml:"unknown,omitempty"` // applied by a newer build
+}
+
+// Status - every migration of a database, oldest first.
+type Status struct {
+	Migrations []Entry `json:"migrations" yaml:"migrations"`
+}
+
+// Current - the latest applied version, if any.
+func (s Status) Current() string {
+	applied := s.Applied()
+	if len(applied) == 0 {
+		return ""
+	}
+
+	return applied[len(applied)-1].Version
+}
+
+func (s Status) Applied() []Entry {
+	return s.filter(func(e Entry) bool { return e.AppliedAt != nil })
+}
+
+func (s Status) Pending() []Entry {
+	return s.filter(func(e Entry) bool { return e.AppliedAt == nil })
+}
+
+func (s Status) Unknown() []Entry {
+	return s.filter(func(e Entry) bool { return e.Unknown })
+}
+
+func (s Status) filter(keep func(Entry) bool) []Entry {
+	var entries []Entry
+	for _, e := range s.Migrations {
+		if keep(e) {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+func (e Entry) state() string {
+	switch {
+	case e.Unknown:
+		return "unknown"
+	case e.AppliedAt != nil:
+		return "applied"
+	}
+
+	return "pending"
+}
+
+func (s Status) String() string {
+	var b strings.Builder
+
+	for _, e := range s.Migrations {
+		fmt.Fprintf(&b, "%-8s %s\n", e.state(), e.Migration)
+	}
+
+	fmt.Fprintf(&b, "%d applied, %d pending\n", len(s.Applied()), len(s.Pending()))
+
+	return b.String()
+}
+
+func (s Status) TableWriter() table.Writer {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleLight)
+	t.SetOutputMirror(nil) // Delegate printing to gout tool
+
+	t.SetTitle("migrations")
+	t.AppendHeader(table.Row{"version", "name", "status", "applied at"})
+
+	for _, e := range s.Migrations {
+		var at string
+		if e.AppliedAt != nil {
+			at = e.AppliedAt.Format(time.DateTime)
+		}
+
+		t.AppendRow(table.Row{e.Version, e.Name, e.state(), at})
+	}
+
+	t.AppendFooter(table.Row{s.Current(), "", fmt.Sprintf("%d pending", len(s.Pending())), ""})
+
+	return t
+}
@@ -0,0 +1,239 @@
+// Package morphy finds the base forms of inflected English words, following the
+// rules of WordNet's morphy: irregular forms are looked up in exception lists,
+// and regular ones have their inflectional suffix detached.
+package morphy
+
+import (
+	"bufio"
+	"embed"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/oleoneto/redic/app/domain/types"
+)
+
+// Exception lists use the format of WordNet's *.exc files, one inflected form per line
+// followed by its base forms, with spaces written as underscores:
+//
+//	geese goose
+//	axes axis axe
+//
+//go:embed exceptions/*.exc
+var exceptionFiles embed.FS
+
+// Lemma - a base form a word may be an inflection of.
+type Lemma struct {
+	Word         string             // i.e goose
+	PartOfSpeech types.PartOfSpeech // i.e n (a stands for both a and s)
+	Irregular    bool               // whether it comes from an exception list rather than a suffix rule
+}
+
+type rule struct{ suffix, ending string }
+
+// Suffixes detached from regular inflections, in the order WordNet tries them.
+var rules = map[types.PartOfSpeech][]rule{
+	types.Noun: {
+		{"s", ""}, {"ses", "s"}, {"xes", "x"}, {"zes", "z"}, {"ches", "ch"}, {"shes", "sh"}, {"men", "man"}, {"ies", "y"},
+	},
+	types.Verb: {
+		{"s", ""}, {"ies", "y"}, {"es", "e"}, {"es", ""}, {"ed", "e"}, {"ed", ""}, {"ing", "e"}, {"ing", ""},
+	},
+	types.Adjective1: {
+		{"er", ""}, {"est", ""}, {"er", "e"}, {"est", "e"},
+	},
+}
+
+// Parts of speech, in the order their lemmas are returned.
+var partsOfSpeech = []types.PartOfSpeech{types.Noun, types.Verb, types.Adjective1, types.Adverb}
+
+// Exception lists, by the name of their file.
+var exceptionLists = map[string]types.PartOfSpeech{
+	"noun.exc": types.Noun,
+	"verb.exc": types.Verb,
+	"adj.exc":  types.Adjective1,
+	"adv.exc":  types.Adverb,
+}
+
+var exceptions = loadExceptions()
+var exceptionsMu sync.RWMutex
+
+// Lemmas - returns the base forms the word may be an inflection of, irregular forms first.
+//
+// Candidates are not checked against a lexicon, so some may not be words at all
+// (i.e morphy("nuts", n) yields "nut" but morphy("bus", n) yields "bu"); callers keep
+// those they can find. The word itself is never returned. Every part of speech is
+// considered when pos is empty or types.ALL. Words of more than one token (i.e "looking up")
+// have each token lemmatized in turn.
+//
+// Usage:
+//
+//	Lemmas("geese", types.Noun)   // [{goose n}]
+//	Lemmas("running", types.Verb) // [{runne v} {runn v} {run v}]
+func Lemmas(word string, pos types.PartOfSpeech) []Lemma {
+	word = strings.ToLower(strings.TrimSpace(word))
+
+	var lemmas []Lemma
+	var seen = map[Lemma]bool{}
+
+	add := func(lemma Lemma) {
+		if lemma.Word == "" || lemma.Word == word || seen[lemma] {
+			return
+		}
+		seen[lemma] = true
+		lemmas = append(lemmas, lemma)
+	}
+
+	for _, p := range partsOfSpeech {
+		if pos != "" && pos != types.ALL && normalize(pos) != p {
+			continue
+		}
+
+		irregular, regular := baseForms(word, p)
+
+		for _, base := range irregular {
+			add(Lemma{Word: base, PartOfSpeech: p, Irregular: true})
+		}
+
+		for _, base := range regular {
+			add(Lemma{Word: base, PartOfSpeech: p})
+		}
+	}
+
+	return lemmas
+}
+
+// baseForms - the base forms of a word of the given part of speech, as found in
+// the exception list and as derived by suffix rules.
+func baseForms(word string, pos types.PartOfSpeech) (irregular []string, regular []string) {
+	if tokens := strings.Fields(word); len(tokens) > 1 {
+		variant := func(i int, base string) string {
+			return strings.Join(append(append(append([]string{}, tokens[:i]...), base), tokens[i+1:]...), " ")
+		}
+
+		for i, token := range tokens {
+			exceptional, ruled := baseForms(token, pos)
+
+			for _, base := range exceptional {
+				irregular = append(irregular, variant(i, base))
+			}
+
+			for _, base := range ruled {
+				regular = append(regular, variant(i, base))
+			}
+		}
+
+		return irregular, regular
+	}
+
+	for _, r := range rules[pos] {
+		if !strings.HasSuffix(word, r.suffix) || len(word) <= len(r.suffix) {
+			continue
+		}
+
+		stem := strings.TrimSuffix(word, r.suffix)
+		regular = append(regular, stem+r.ending)
+
+		// i.e running → run, bigger → big
+		if r.ending == "" && r.suffix != "s" && undoubles(stem) {
+			regular = append(regular, stem[:len(stem)-1])
+		}
+	}
+
+	exceptionsMu.RLock()
+	defer exceptionsMu.RUnlock()
+
+	return exceptions[pos][word], regular
+}
+
+// undoubles - whether a stem ends in a doubled consonant that its base form does not have.
+func undoubles(stem string) bool {
+	n := len(stem)
+	if n < 3 || stem[n-1] != stem[n-2] {
+		return false
+	}
+
+	return !strings.ContainsRune("aeiouslz", rune(stem[n-1]))
+}
+
+// normalize - satellite adjectives inflect like any other adjective.
+func normalize(pos types.PartOfSpeech) types.PartOfSpeech {
+	if pos == types.Adjective2 {
+		return types.Adjective1
+	}
+	return pos
+}
+
+// ExceptionList - the part of speech of an exception list, named like WordNet's (i.e adj.exc).
+func ExceptionList(name string) (types.PartOfSpeech, bool) {
+	pos, ok := exceptionLists[path.Base(name)]
+	return pos, ok
+}
+
+// AddExceptions - adds the irregular forms of an exception list to those of the
+// part of speech (i.e the *.exc files of a WordNet release). Base forms already known are kept once.
+func AddExceptions(pos types.PartOfSpeech, r io.Reader) error {
+	var list = map[string][]string{}
+	if err := readExceptions(r, list); err != nil {
+		return err
+	}
+
+	exceptionsMu.Lock()
+	defer exceptionsMu.Unlock()
+
+	pos = normalize(pos)
+	if exceptions[pos] == nil {
+		exceptions[pos] = map[string][]string{}
+	}
+
+	for word, bases := range list {
+		for _, base := range bases {
+			if !slices.Contains(exceptions[pos][word], base) {
+				exceptions[pos][word] = append(exceptions[pos][word], base)
+			}
+		}
+	}
+
+	return nil
+}
+
+func loadExceptions() map[types.PartOfSpeech]map[string][]string {
+	var lists = map[types.PartOfSpeech]map[string][]string{}
+
+	for name, pos := range exceptionLists {
+		lists[pos] = map[string][]string{}
+
+		f, err := exceptionFiles.Open(path.Join("exceptions", name))
+		if err != nil {
+			panic(err)
+		}
+
+		if err := readExceptions(f, lists[pos]); err != nil {
+			panic(err)
+		}
+
+		f.Close()
+	}
+
+	return lists
+}
+
+func readExceptions(r io.Reader, list map[string][]string) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		for i := range fields {
+			fields[i] = strings.ReplaceAll(fields[i], "_", " ")
+		}
+
+		list[fields[0]] = append(list[fields[0]], fields[1:]...)
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,121 @@
+package morphy_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/morphy"
+	"github.com/oleoneto/redic/app/pkg/search"
+)
+
+func Test_Lemmas(t *testing.T) {
+	const irregular = true
+
+	tests := []struct {
+		word string
+		pos  types.PartOfSpeech
+		want []morphy.Lemma
+	}{
+		{"geese", types.Noun, []morphy.Lemma{{"goose", types.Noun, irregular}}},
+		{"Churches", types.Noun, []morphy.Lemma{{"churche", types.Noun, false}, {"church", types.Noun, false}}},
+		{"ponies", types.Noun, []morphy.Lemma{{"ponie", types.Noun, false}, {"pony", types.Noun, false}}},
+		{"running", types.Verb, []morphy.Lemma{{"runne", types.Verb, false}, {"runn", types.Verb, false}, {"run", types.Verb, false}}},
+		{"went", types.Verb, []morphy.Lemma{{"go", types.Verb, irregular}}},
+		{"bigger", types.Adjective2, []morphy.Lemma{{"bigg", types.Adjective1, false}, {"big", types.Adjective1, false}, {"bigge", types.Adjective1, false}}},
+		{"better", types.ALL, []morphy.Lemma{
+			{"good", types.Adjective1, irregular},
+			{"well", types.Adjective1, irregular},
+			{"bett", types.Adjective1, false},
+			{"bet", types.Adjective1, false},
+			{"bette", types.Adjective1, false},
+			{"well", types.Adverb, irregular},
+		}},
+		{"looking up", types.Verb, []morphy.Lemma{{"looke up", types.Verb, false}, {"look up", types.Verb, false}}},
+		{"went out", types.Verb, []morphy.Lemma{{"go out", types.Verb, irregular}}},
+		{"quickly", types.Adverb, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := morphy.Lemmas(tt.word, tt.pos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lemmas(%q, %q) = %v, want %v", tt.word, tt.pos, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_AddExceptions(t *testing.T) {
+	pos, ok := morphy.ExceptionList("dict/verb.exc")
+	if !ok || pos != types.Verb {
+		t.Fatalf("ExceptionList() = %q, %v, want %q, true", pos, ok, types.Verb)
+	}
+
+	if err := morphy.AddExceptions(pos, strings.NewReader("frobbed frob\nwent go\nwent_out go_out\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		word string
+		want []morphy.Lemma
+	}{
+		{"frobbed", []morphy.Lemma{{"frob", types.Verb, true}, {"frobbe", types.Verb, false}, {"frobb", types.Verb, false}, {"frob", types.Verb, false}}},
+		{"went", []morphy.Lemma{{"go", types.Verb, true}}},
+		{"went out", []morphy.Lemma{{"go out", types.Verb, true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := morphy.Lemmas(tt.word, types.Verb); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lemmas(%q) = %v, want %v", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ExpandQuery(t *testing.T) {
+	words := map[string]bool{"dog": true, "bark": true, "go": true}
+
+	known := func(candidates []string) (map[string]bool, error) {
+		var res = map[string]bool{}
+		for _, word := range candidates {
+			res[word] = words[word]
+		}
+		return res, nil
+	}
+
+	tests := []struct {
+		query  string
+		want   string
+		lemmas map[string][]string
+	}{
+		{"barking dogs", `("barking" OR "bark") AND ("dogs" OR "dog")`, map[string][]string{"barking": {"bark"}, "dogs": {"dog"}}},
+		{"small dogs", `"small" AND ("dogs" OR "dog")`, map[string][]string{"dogs": {"dog"}}},
+		{"dogs OR cats", `("dogs" OR "dog" OR "cats")`, map[string][]string{"dogs": {"dog"}}},
+		{`"barking dogs" dog*`, `"barking dogs" AND "dog"*`, nil},
+		{"cats", `"cats"`, nil},
+		{"goes", `("goes" OR "go")`, map[string][]string{"goes": {"go"}}},
+		{"dogs -barks", `("dogs" OR "dog") NOT ("barks" OR "bark")`, map[string][]string{"dogs": {"dog"}, "barks": {"bark"}}},
+		{"(small dogs) OR cats", `("small" AND ("dogs" OR "dog") OR "cats")`, map[string][]string{"dogs": {"dog"}}},
+		{"NEAR(barking dogs)", `NEAR("barking" "dogs", 10)`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := search.Parse(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expanded, lemmas, err := morphy.ExpandQuery(query, known)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := search.FTS5(expanded); got != tt.want || !reflect.DeepEqual(lemmas, tt.lemmas) {
+				t.Errorf("ExpandQuery(%q) = %s, %v, want %s, %v", tt.query, got, lemmas, tt.want, tt.lemmas)
+			}
+		})
+	}
+}
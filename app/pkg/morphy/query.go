@@ -0,0 +1,133 @@
+package morphy
+
+import (
+	"unicode"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+	"github.com/oleoneto/redic/app/pkg/search"
+)
+
+// ExpandQuery - Extends every inflected word of a query with its base forms
+// that are known words, i.e `barking dogs` → `barking (dogs OR "dog")`.
+//
+// Only words written on their own are extended; quoted phrases, prefixes (dog*),
+// hyphenated words and NEAR groups are left as they are.
+// known is given every candidate base form and returns those that are words of the dictionary.
+// Returns the query along with the lemmas of each word that was extended.
+func ExpandQuery(query search.Expr, known func(candidates []string) (map[string]bool, error)) (search.Expr, map[string][]string, error) {
+	var candidates = map[string][]string{}
+	var words = []string{}
+
+	var collect func(search.Expr)
+	collect = func(e search.Expr) {
+		switch e := e.(type) {
+		case search.Phrase:
+			if !plainWord(e) {
+				return
+			}
+
+			for _, lemma := range Lemmas(e.Words[0], types.ALL) {
+				if !helpers.Contains(candidates[e.Words[0]], lemma.Word) {
+					candidates[e.Words[0]] = append(candidates[e.Words[0]], lemma.Word)
+					words = append(words, lemma.Word)
+				}
+			}
+		case search.Any:
+			for _, alternative := range e.Alternatives {
+				collect(alternative)
+			}
+		case search.All:
+			for _, required := range e.Required {
+				collect(required)
+			}
+			for _, excluded := range e.Excluded {
+				collect(excluded)
+			}
+		}
+	}
+
+	collect(query)
+
+	if len(words) == 0 {
+		return query, nil, nil
+	}
+
+	found, err := known(words)
+	if err != nil {
+		return query, nil, err
+	}
+
+	var lemmas = map[string][]string{}
+
+	var expand func(search.Expr) search.Expr
+	expand = func(e search.Expr) search.Expr {
+		switch e := e.(type) {
+		case search.Phrase:
+			if !plainWord(e) {
+				return e
+			}
+
+			var alternatives = []search.Expr{e}
+
+			for _, lemma := range candidates[e.Words[0]] {
+				if found[lemma] {
+					alternatives = append(alternatives, search.Phrase{Words: []string{lemma}, Quoted: true})
+					lemmas[e.Words[0]] = append(lemmas[e.Words[0]], lemma)
+				}
+			}
+
+			if len(alternatives) == 1 {
+				return e
+			}
+
+			return search.Any{Alternatives: alternatives}
+		case search.Any:
+			var alternatives []search.Expr
+
+			/* Base forms join the alternatives a word already had */
+			for _, alternative := range e.Alternatives {
+				expanded := expand(alternative)
+
+				if group, ok := expanded.(search.Any); ok {
+					alternatives = append(alternatives, group.Alternatives...)
+				} else {
+					alternatives = append(alternatives, expanded)
+				}
+			}
+
+			return search.Any{Alternatives: alternatives}
+		case search.All:
+			return search.All{
+				Required: helpers.Map(e.Required, func(_ int, e search.Expr) search.Expr { return expand(e) }),
+				Excluded: helpers.Map(e.Excluded, func(_ int, e search.Expr) search.Expr { return expand(e) }),
+			}
+		}
+
+		return e
+	}
+
+	query = expand(query)
+
+	if len(lemmas) == 0 {
+		return query, nil, nil
+	}
+
+	return query, lemmas, nil
+}
+
+// plainWord - whether a phrase is a word written on its own, rather than quoted or a prefix.
+// Words too short to be inflected (i.e as, is) are left out.
+func plainWord(p search.Phrase) bool {
+	if p.Quoted || p.Prefix || len(p.Words) != 1 {
+		return false
+	}
+
+	for _, r := range p.Words[0] {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+
+	return len([]rune(p.Words[0])) > 3
+}
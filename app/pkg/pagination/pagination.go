@@ -0,0 +1,75 @@
+// Package pagination signs the cursors searches are paged by, so clients can hand them
+// back as they are but cannot forge them, nor carry them over to another search.
+//
+// Usage:
+//
+//	token := pagination.Encode(secret, scope, types.Cursor{Rank: 22.1, Word: "puppy", Id: 33636, SynsetId: "01325095-n"})
+//	cursor, err := pagination.Decode(secret, scope, token)
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/oleoneto/redic/app/domain/types"
+)
+
+var encoding = base64.RawURLEncoding
+
+// Secret - a new random key to sign cursors with.
+func Secret() []byte {
+	var secret = make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	return secret
+}
+
+// Encode - the cursor, as an opaque token that only decodes with the same secret and scope.
+// The scope names the search the cursor pages through (i.e its query and filters).
+func Encode(secret []byte, scope string, cursor types.Cursor) string {
+	payload, _ := json.Marshal(cursor)
+
+	return encoding.EncodeToString(payload) + "." + encoding.EncodeToString(sign(secret, scope, payload))
+}
+
+// Decode - the cursor in a token made by Encode. Tokens that were altered,
+// or made for another scope, are an ErrInvalidPage.
+func Decode(secret []byte, scope string, token string) (types.Cursor, error) {
+	var cursor types.Cursor
+	var invalid = fmt.Errorf("%w: cursor %q is not one this search handed out", types.ErrInvalidPage, token)
+
+	p, s, ok := strings.Cut(token, ".")
+	if !ok {
+		return cursor, invalid
+	}
+
+	payload, err := encoding.DecodeString(p)
+	if err != nil {
+		return cursor, invalid
+	}
+
+	signature, err := encoding.DecodeString(s)
+	if err != nil || !hmac.Equal(signature, sign(secret, scope, payload)) {
+		return cursor, invalid
+	}
+
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return cursor, invalid
+	}
+
+	return cursor, nil
+}
+
+func sign(secret []byte, scope string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(scope))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,48 @@
+package pagination_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/pagination"
+)
+
+func Test_Encode_Decode(t *testing.T) {
+	secret := []byte("secret")
+	cursor := types.Cursor{Rank: 22.11809150520614, Word: "puppy", Id: 33636, SynsetId: "01325095-n"}
+	token := pagination.Encode(secret, "young dog", cursor)
+
+	tests := []struct {
+		name   string
+		secret []byte
+		scope  string
+		token  string
+		valid  bool
+	}{
+		{name: "as handed out", secret: secret, scope: "young dog", token: token, valid: true},
+		{name: "another secret", secret: []byte("other"), scope: "young dog", token: token},
+		{name: "another search", secret: secret, scope: "old dog", token: token},
+		{name: "altered", secret: secret, scope: "young dog", token: "f" + token[1:]},
+		{name: "unsigned", secret: secret, scope: "young dog", token: strings.Split(token, ".")[0]},
+		{name: "garbage", secret: secret, scope: "young dog", token: "not a cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pagination.Decode(tt.secret, tt.scope, tt.token)
+
+			if !tt.valid {
+				if !errors.Is(err, types.ErrInvalidPage) {
+					t.Errorf("Decode(%q) error = %v, want %v", tt.token, err, types.ErrInvalidPage)
+				}
+				return
+			}
+
+			if err != nil || got != cursor {
+				t.Errorf("Decode(%q) = %v, %v, want %v", tt.token, got, err, cursor)
+			}
+		})
+	}
+}
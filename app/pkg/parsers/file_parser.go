@@ -1,20 +1,21 @@
 package parsers
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"io/fs"
 	"path/filepath"
-	"strings"
 
 	"github.com/oleoneto/redic/app/domain/protocols"
 	"github.com/oleoneto/redic/app/domain/types"
 	"github.com/sirupsen/logrus"
-	"gopkg.in/yaml.v3"
 )
 
 type Parser struct {
 	loader protocols.LoaderFunc
 	reader protocols.ReaderFunc
+	opener protocols.OpenerFunc
 }
 
 var _ protocols.FileParserProtocol = (*Parser)(nil)
@@ -23,6 +24,12 @@ func DefaultParser(l protocols.LoaderFunc, r protocols.ReaderFunc) *Parser {
 	return &Parser{loader: l, reader: r}
 }
 
+// StreamingParser - reads files incrementally through the given opener,
+// so memory use is bounded by the largest synset rather than the largest file.
+func StreamingParser(l protocols.LoaderFunc, o protocols.OpenerFunc) *Parser {
+	return &Parser{loader: l, opener: o}
+}
+
 func (p *Parser) LoadFiles(ctx context.Context, dir string) []fs.DirEntry {
 	files, err := p.loader(dir)
 	if err != nil {
@@ -32,70 +39,52 @@ func (p *Parser) LoadFiles(ctx context.Context, dir string) []fs.DirEntry {
 }
 
 func (p *Parser) ParseFile(ctx context.Context, dir string, file fs.DirEntry) (*types.ParsedFile, error) {
-	if file.IsDir() || !strings.HasSuffix(file.Name(), ".yaml") {
+	if !isYAML(file) {
 		logrus.Errorln(file.Name(), "Error: Skipping invalid YAML file.")
 		return nil, nil
 	}
 
-	path := filepath.Join(dir, file.Name())
-
-	contents, err := p.reader(path)
-	if err != nil {
-		logrus.Errorln(file.Name(), "Error:", err)
-		return nil, err
-	}
-
-	var pf map[string]types.DictEntry
-	err = yaml.Unmarshal(contents, &pf)
-	if err != nil {
-		return nil, err
-	}
-
-	return &types.ParsedFile{Data: pf, Name: file.Name()}, nil
+	return collect(ctx, p, dir, file)
 }
 
 func (p *Parser) ParseFiles(ctx context.Context, dir string, files []fs.DirEntry, capturer protocols.CaptureFunc) ([]types.ParsedFile, error) {
-	var entries = []types.ParsedFile{}
-
-	for _, de := range files {
-		parsedFile, err := p.ParseFile(ctx, dir, de)
-		if err != nil || parsedFile == nil {
-			return nil, err
-		}
-
-		entries = append(entries, *parsedFile)
+	return collectAll(ctx, p, dir, files, capturer)
+}
 
-		go capturer(parsedFile)
+// StreamFile - decodes the synsets of a single file one at a time.
+//
+// The entries channel is closed once the file is exhausted, the context is cancelled,
+// or decoding fails. The error channel then yields at most one error.
+func (p *Parser) StreamFile(ctx context.Context, dir string, file fs.DirEntry) (<-chan types.DictEntry, <-chan error) {
+	if !isYAML(file) {
+		logrus.Errorln(file.Name(), "Error: Skipping invalid YAML file.")
+		return closed()
 	}
 
-	return entries, nil
+	open := func() (io.ReadCloser, error) { return p.open(filepath.Join(dir, file.Name())) }
+
+	return stream(ctx, file.Name(), open, DecodeEntries)
 }
 
-func (p *Parser) ParseFilesChan(ctx context.Context, dir string, files []fs.DirEntry) <-chan map[string]types.DictEntry {
-	dst := make(chan map[string]types.DictEntry)
+// StreamFiles - decodes the synsets of every file in order, one at a time.
+func (p *Parser) StreamFiles(ctx context.Context, dir string, files []fs.DirEntry) (<-chan types.DictEntry, <-chan error) {
+	return streamAll(ctx, p, dir, files)
+}
 
-	var next int
-	var total = len(files)
+func (p *Parser) open(path string) (io.ReadCloser, error) {
+	if p.opener != nil {
+		return p.opener(path)
+	}
 
-	parsedFile, err := p.ParseFile(ctx, dir, files[next])
-	if err != nil || parsedFile == nil {
-		return nil
+	contents, err := p.reader(path)
+	if err != nil {
+		return nil, err
 	}
 
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return // prevent leak
-			case dst <- parsedFile.Data:
-				if next > total {
-					close(dst)
-					return // prevent leak
-				}
-				next++
-			}
-		}
-	}()
-
-	return dst
+	return io.NopCloser(bytes.NewReader(contents)), nil
+}
+
+func isYAML(file fs.DirEntry) bool {
+	format, ok := DetectFormat(file.Name())
+	return !file.IsDir() && ok && format == YAML
 }
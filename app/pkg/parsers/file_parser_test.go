@@ -0,0 +1,130 @@
+package parsers_test
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/parsers"
+)
+
+const sample = `00003552-s:
+  definition:
+  - coming into existence
+  example:
+  - an emergent republic
+  ili: i10
+  members:
+  - emergent
+  - emerging
+  partOfSpeech: s
+  similar:
+  - 00003356-a
+09818957-n:
+  definition:
+  - someone who tries to bring peace by acceding to demands
+  example:
+  - source: Winston Churchill
+    text: An appeaser is one who feeds a crocodile--hoping it will eat him last
+  ili: i88255
+  members:
+  - appeaser
+  partOfSpeech: n
+`
+
+func Test_DecodeEntries(t *testing.T) {
+	var got []types.DictEntry
+
+	err := parsers.DecodeEntries(strings.NewReader(sample), func(e types.DictEntry) error {
+		got = append(got, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeEntries() error = %v", err)
+	}
+
+	want := []types.DictEntry{
+		{
+			Id:           "00003552-s",
+			Definitions:  []string{"coming into existence"},
+			Examples:     []types.Example{{Text: "an emergent republic"}},
+			Members:      []string{"emergent", "emerging"},
+			PartOfSpeech: "s",
+			Similar:      []string{"00003356-a"},
+			Identifier:   "i10",
+		},
+		{
+			Id:          "09818957-n",
+			Definitions: []string{"someone who tries to bring peace by acceding to demands"},
+			Examples: []types.Example{{
+				Text:   "An appeaser is one who feeds a crocodile--hoping it will eat him last",
+				Source: "Winston Churchill",
+			}},
+			Members:      []string{"appeaser"},
+			PartOfSpeech: "n",
+			Identifier:   "i88255",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeEntries() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_DecodeEntries_Error(t *testing.T) {
+	err := parsers.DecodeEntries(strings.NewReader("00003552-s:\n  members: [\n"), func(types.DictEntry) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("DecodeEntries() error = %v, want an error at line 1", err)
+	}
+}
+
+func Test_StreamFile(t *testing.T) {
+	fsys := fstest.MapFS{"english/adj.all.yaml": {Data: []byte(sample)}}
+
+	parser := parsers.StreamingParser(
+		func(dir string) ([]fs.DirEntry, error) { return fs.ReadDir(fsys, dir) },
+		func(name string) (io.ReadCloser, error) { return fsys.Open(name) },
+	)
+
+	files := parser.LoadFiles(context.Background(), "english")
+	if len(files) != 1 {
+		t.Fatalf("LoadFiles() = %v, want 1 file", len(files))
+	}
+
+	t.Run("all entries", func(t *testing.T) {
+		entries, errs := parser.StreamFile(context.Background(), "english", files[0])
+
+		var ids []string
+		for entry := range entries {
+			if entry.File != "adj.all.yaml" {
+				t.Errorf("entry.File = %v, want adj.all.yaml", entry.File)
+			}
+			ids = append(ids, entry.Id)
+		}
+
+		if err := <-errs; err != nil {
+			t.Fatalf("StreamFile() error = %v", err)
+		}
+
+		if want := []string{"00003552-s", "09818957-n"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("StreamFile() = %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		entries, errs := parser.StreamFile(ctx, "english", files[0])
+		<-entries
+		cancel()
+
+		if err := <-errs; err == nil {
+			t.Errorf("StreamFile() error = nil, want context.Canceled")
+		}
+	})
+}
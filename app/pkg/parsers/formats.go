@@ -0,0 +1,137 @@
+package parsers
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/sirupsen/logrus"
+)
+
+// Format - the layout of a dictionary file, as told by its extension.
+type Format string
+
+const (
+	YAML Format = "yaml" // one file per lexicographer file, keyed by synset id
+	LMF  Format = "lmf"  // Global WordNet LMF (XML), optionally gzipped
+	WNDB Format = "wndb" // Princeton WordNet database files (data.noun, data.verb, ...)
+
+	// Flat glossaries, one word and definition per row
+	CSV   Format = "csv"
+	TSV   Format = "tsv"
+	JSONL Format = "jsonl"
+)
+
+// Formats - every format redic can import.
+var Formats = []Format{YAML, LMF, WNDB, CSV, TSV, JSONL}
+
+// GlossaryFormats - the formats redic can both import and export.
+var GlossaryFormats = []Format{CSV, TSV, JSONL}
+
+// DetectFormat - returns the format of the named file, if it is supported.
+func DetectFormat(name string) (Format, bool) {
+	if isWNDB(name) {
+		return WNDB, true
+	}
+
+	switch name = strings.ToLower(name); {
+	case strings.HasSuffix(name, ".yaml"), strings.HasSuffix(name, ".yml"):
+		return YAML, true
+	case strings.HasSuffix(name, ".xml"), strings.HasSuffix(name, ".xml.gz"):
+		return LMF, true
+	case strings.HasSuffix(name, ".csv"):
+		return CSV, true
+	case strings.HasSuffix(name, ".tsv"):
+		return TSV, true
+	case strings.HasSuffix(name, ".jsonl"), strings.HasSuffix(name, ".ndjson"):
+		return JSONL, true
+	}
+
+	return "", false
+}
+
+// Supported - whether any parser can read the given file.
+func Supported(file fs.DirEntry) bool {
+	_, ok := DetectFormat(file.Name())
+	return !file.IsDir() && ok
+}
+
+// AutoParser - hands every file to the parser of its format.
+// Files of unknown formats are left out by LoadFiles.
+type AutoParser struct {
+	loader  protocols.LoaderFunc
+	opener  protocols.OpenerFunc
+	format  Format
+	columns Columns
+}
+
+var _ protocols.FileParserProtocol = (*AutoParser)(nil)
+
+func NewAutoParser(l protocols.LoaderFunc, o protocols.OpenerFunc) *AutoParser {
+	return &AutoParser{loader: l, opener: o, columns: Columns{}}
+}
+
+// WithFormat - reads every file as the given format, whatever its extension.
+func (p *AutoParser) WithFormat(format Format) *AutoParser {
+	p.format = format
+	return p
+}
+
+// WithColumns - maps glossary fields to the columns of CSV, TSV and JSONL files.
+func (p *AutoParser) WithColumns(columns Columns) *AutoParser {
+	p.columns = columns
+	return p
+}
+
+func (p *AutoParser) LoadFiles(ctx context.Context, dir string) []fs.DirEntry {
+	files, err := p.loader(dir)
+	if err != nil {
+		return nil
+	}
+
+	var supported []fs.DirEntry
+	for _, file := range files {
+		if Supported(file) || (p.format != "" && !file.IsDir()) {
+			supported = append(supported, file)
+		}
+	}
+
+	return supported
+}
+
+func (p *AutoParser) ParseFile(ctx context.Context, dir string, file fs.DirEntry) (*types.ParsedFile, error) {
+	return collect(ctx, p, dir, file)
+}
+
+func (p *AutoParser) ParseFiles(ctx context.Context, dir string, files []fs.DirEntry, capturer protocols.CaptureFunc) ([]types.ParsedFile, error) {
+	return collectAll(ctx, p, dir, files, capturer)
+}
+
+func (p *AutoParser) StreamFile(ctx context.Context, dir string, file fs.DirEntry) (<-chan types.DictEntry, <-chan error) {
+	format, ok := DetectFormat(file.Name())
+	if p.format != "" {
+		format, ok = p.format, true
+	}
+
+	if !ok || file.IsDir() {
+		logrus.Errorln(file.Name(), "Error: Skipping file of unknown format.")
+		return closed()
+	}
+
+	return p.parser(format).StreamFile(ctx, dir, file)
+}
+
+func (p *AutoParser) parser(format Format) protocols.FileParserProtocol {
+	switch format {
+	case LMF:
+		return NewLMFParser(p.loader, p.opener)
+	case WNDB:
+		return NewWNDBParser(p.loader, p.opener)
+	case CSV, TSV, JSONL:
+		return NewGlossaryParser(p.loader, p.opener, format, p.columns)
+	}
+
+	return StreamingParser(p.loader, p.opener)
+}
@@ -0,0 +1,381 @@
+package parsers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+)
+
+// Fields of a glossary row, in the order they are written.
+var GlossaryFields = []string{"word", "part_of_speech", "definition", "explicit", "example", "source", "synset", "language", "ili", "lexfile", "relations"}
+
+// Columns - maps glossary fields to the column names (or JSONL keys) of a file,
+// i.e {"word": "Term", "definition": "Meaning"}. Unmapped fields keep their own name.
+type Columns map[string]string
+
+// ParseColumns - parses a column mapping of the form field=column[,field=column...].
+//
+// Usage:
+//
+//	ParseColumns("word=Term,definition=Meaning,part_of_speech=POS")
+func ParseColumns(mapping string) (Columns, error) {
+	var columns = Columns{}
+
+	for _, pair := range strings.Split(mapping, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		field, column, ok := strings.Cut(pair, "=")
+		field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+
+		if !ok || column == "" {
+			return nil, fmt.Errorf("invalid column mapping: %q (expected field=column)", pair)
+		}
+
+		if !helpers.Contains(GlossaryFields, field) {
+			return nil, fmt.Errorf("unknown glossary field: %q (expected one of %s)", field, strings.Join(GlossaryFields, ", "))
+		}
+
+		columns[field] = column
+	}
+
+	return columns, nil
+}
+
+// Column - the name of the column holding the given field.
+func (c Columns) Column(field string) string {
+	if column, ok := c[field]; ok {
+		return column
+	}
+	return field
+}
+
+// GlossaryParser reads flat glossaries (CSV, TSV or JSONL), one word and definition per row.
+type GlossaryParser struct {
+	loader  protocols.LoaderFunc
+	opener  protocols.OpenerFunc
+	format  Format
+	columns Columns
+}
+
+var _ protocols.FileParserProtocol = (*GlossaryParser)(nil)
+
+func NewGlossaryParser(l protocols.LoaderFunc, o protocols.OpenerFunc, format Format, columns Columns) *GlossaryParser {
+	return &GlossaryParser{loader: l, opener: o, format: format, columns: columns}
+}
+
+func (p *GlossaryParser) LoadFiles(ctx context.Context, dir string) []fs.DirEntry {
+	files, err := p.loader(dir)
+	if err != nil {
+		return nil
+	}
+	return files
+}
+
+func (p *GlossaryParser) ParseFile(ctx context.Context, dir string, file fs.DirEntry) (*types.ParsedFile, error) {
+	return collect(ctx, p, dir, file)
+}
+
+func (p *GlossaryParser) ParseFiles(ctx context.Context, dir string, files []fs.DirEntry, capturer protocols.CaptureFunc) ([]types.ParsedFile, error) {
+	return collectAll(ctx, p, dir, files, capturer)
+}
+
+func (p *GlossaryParser) StreamFile(ctx context.Context, dir string, file fs.DirEntry) (<-chan types.DictEntry, <-chan error) {
+	open := func() (io.ReadCloser, error) { return p.opener(filepath.Join(dir, file.Name())) }
+
+	return stream(ctx, file.Name(), open, DecodeGlossary(p.format, p.columns))
+}
+
+// DecodeGlossary - returns a decoder for glossaries of the given format.
+//
+// Rows are grouped into synsets by their synset column or, when it is empty, by their
+// part of speech and definition (see types.LocalSynsetId). Since rows of one synset
+// may be anywhere in the file, synsets are emitted once the whole file has been read.
+func DecodeGlossary(format Format, columns Columns) Decoder {
+	return func(r io.Reader, emit func(types.DictEntry) error) error {
+		var entries []*types.DictEntry
+		var index = map[string]*types.DictEntry{}
+
+		add := func(row types.GlossaryEntry) {
+			if row.Synset == "" {
+				row.Synset = types.LocalSynsetId(row.PartOfSpeech, row.Definition)
+			}
+
+			key := fmt.Sprintf("%s/%s/%t", row.Language, row.Synset, row.Explicit)
+
+			entry, ok := index[key]
+			if !ok {
+				entry = &types.DictEntry{
+					Id:           row.Synset,
+					Definitions:  []string{row.Definition},
+					PartOfSpeech: row.PartOfSpeech,
+					Explicit:     row.Explicit,
+					Language:     row.Language,
+					Identifier:   row.ILI,
+					Lexfile:      row.Lexfile,
+				}
+
+				index[key] = entry
+				entries = append(entries, entry)
+			}
+
+			if !helpers.Contains(entry.Members, row.Word) {
+				entry.Members = append(entry.Members, row.Word)
+			}
+
+			if row.Example != "" {
+				entry.Examples = append(entry.Examples, types.Example{Text: row.Example, Source: row.Source})
+			}
+
+			/* Relations were checked with the rest of the row */
+			relations, _ := types.ParseRelations(row.Relations)
+			for _, relation := range relations {
+				if !entry.HasRelation(relation.Type, relation.Target) {
+					entry.AddRelation(relation.Type, relation.Target)
+				}
+			}
+		}
+
+		var err error
+
+		switch format {
+		case CSV:
+			err = decodeDelimited(r, ',', columns, add)
+		case TSV:
+			err = decodeDelimited(r, '\t', columns, add)
+		case JSONL:
+			err = decodeJSONLines(r, columns, add)
+		default:
+			err = fmt.Errorf("%s is not a glossary format", format)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if err := emit(*entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func decodeDelimited(r io.Reader, comma rune, columns Columns, add func(types.GlossaryEntry)) error {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.LazyQuotes = comma == '\t'
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var positions = map[string]int{}
+
+	for _, field := range GlossaryFields {
+		for i, name := range header {
+			if strings.EqualFold(strings.TrimSpace(name), columns.Column(field)) {
+				positions[field] = i
+			}
+		}
+	}
+
+	for _, field := range []string{"word", "part_of_speech", "definition"} {
+		if _, ok := positions[field]; !ok {
+			return fmt.Errorf("missing column %q for field %s", columns.Column(field), field)
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		row, err := glossaryRow(func(field string) any {
+			if i, ok := positions[field]; ok && i < len(record) {
+				return record[i]
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		add(row)
+	}
+}
+
+func decodeJSONLines(r io.Reader, columns Columns, add func(types.GlossaryEntry)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var line int
+
+	for scanner.Scan() {
+		line++
+
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var object map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &object); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		row, err := glossaryRow(func(field string) any { return object[columns.Column(field)] })
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		add(row)
+	}
+
+	return scanner.Err()
+}
+
+// glossaryRow - builds a row out of the value of each field, as read from a file.
+func glossaryRow(value func(field string) any) (types.GlossaryEntry, error) {
+	// Free text is kept verbatim, so that exported glossaries are imported unchanged.
+	text := func(field string) string {
+		if v := value(field); v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+
+	row := types.GlossaryEntry{
+		Word:         strings.TrimSpace(text("word")),
+		PartOfSpeech: strings.TrimSpace(text("part_of_speech")),
+		Definition:   text("definition"),
+		Example:      text("example"),
+		Source:       text("source"),
+		Synset:       strings.TrimSpace(text("synset")),
+		ILI:          strings.TrimSpace(text("ili")),
+		Lexfile:      strings.TrimSpace(text("lexfile")),
+		Relations:    strings.TrimSpace(text("relations")),
+	}
+
+	if _, err := types.ParseRelations(row.Relations); err != nil {
+		return row, err
+	}
+
+	if lang := strings.TrimSpace(text("language")); lang != "" {
+		var err error
+		if row.Language, err = types.NormalizeLanguage(lang); err != nil {
+			return row, err
+		}
+	}
+
+	if explicit := strings.TrimSpace(text("explicit")); explicit != "" {
+		var err error
+		if row.Explicit, err = strconv.ParseBool(explicit); err != nil {
+			return row, fmt.Errorf("explicit must be true or false, got %q", explicit)
+		}
+	}
+
+	switch types.PartOfSpeech(row.PartOfSpeech) {
+	case types.Noun, types.Verb, types.Adjective1, types.Adjective2, types.Adverb:
+	default:
+		return row, fmt.Errorf("unknown part of speech %q for %q (expected one of n, v, a, s, r)", row.PartOfSpeech, row.Word)
+	}
+
+	if row.Word == "" || (strings.TrimSpace(row.Definition) == "" && row.ILI == "") {
+		return row, fmt.Errorf("every row needs a word and a definition (or an ili)")
+	}
+
+	return row, nil
+}
+
+// GlossaryWriter writes glossary rows as CSV, TSV or JSONL.
+type GlossaryWriter struct {
+	columns Columns
+	csv     *csv.Writer
+	json    *json.Encoder
+	header  bool
+}
+
+func NewGlossaryWriter(w io.Writer, format Format, columns Columns) (*GlossaryWriter, error) {
+	var g = &GlossaryWriter{columns: columns}
+
+	switch format {
+	case CSV, TSV:
+		g.csv = csv.NewWriter(w)
+		if format == TSV {
+			g.csv.Comma = '\t'
+		}
+	case JSONL:
+		g.json = json.NewEncoder(w)
+	default:
+		return nil, fmt.Errorf("%s is not a glossary format", format)
+	}
+
+	return g, nil
+}
+
+func (g *GlossaryWriter) Write(row types.GlossaryEntry) error {
+	values := []any{row.Word, row.PartOfSpeech, row.Definition, row.Explicit, row.Example, row.Source, row.Synset, row.Language, row.ILI, row.Lexfile, row.Relations}
+
+	if g.json != nil {
+		var object = make(map[string]any, len(values))
+		for i, field := range GlossaryFields {
+			object[g.columns.Column(field)] = values[i]
+		}
+
+		return g.json.Encode(object)
+	}
+
+	if !g.header {
+		g.header = true
+
+		var header = make([]string, len(GlossaryFields))
+		for i, field := range GlossaryFields {
+			header[i] = g.columns.Column(field)
+		}
+
+		if err := g.csv.Write(header); err != nil {
+			return err
+		}
+	}
+
+	var record = make([]string, len(values))
+	for i, v := range values {
+		record[i] = fmt.Sprint(v)
+	}
+
+	return g.csv.Write(record)
+}
+
+// Flush - writes any buffered rows to the underlying writer.
+func (g *GlossaryWriter) Flush() error {
+	if g.csv == nil {
+		return nil
+	}
+
+	g.csv.Flush()
+	return g.csv.Error()
+}
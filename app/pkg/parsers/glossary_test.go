@@ -0,0 +1,152 @@
+package parsers_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/parsers"
+)
+
+func Test_DecodeGlossary(t *testing.T) {
+	columns, err := parsers.ParseColumns("word=Term, definition=Meaning, part_of_speech=POS")
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		format parsers.Format
+		input  string
+	}{
+		{
+			name:   "csv",
+			format: parsers.CSV,
+			input: "Term,POS,Meaning,example\n" +
+				"idempotent,a,\"producing the same result, however often applied\",retries are safe when idempotent\n" +
+				"nullipotent,a,having no side effects,\n" +
+				"idempotence,a,\"producing the same result, however often applied\",\n",
+		},
+		{
+			name:   "tsv",
+			format: parsers.TSV,
+			input: "Term\tPOS\tMeaning\texample\n" +
+				"idempotent\ta\tproducing the same result, however often applied\tretries are safe when idempotent\n" +
+				"nullipotent\ta\thaving no side effects\t\n" +
+				"idempotence\ta\tproducing the same result, however often applied\t\n",
+		},
+		{
+			name:   "jsonl",
+			format: parsers.JSONL,
+			input: `{"Term": "idempotent", "POS": "a", "Meaning": "producing the same result, however often applied", "example": "retries are safe when idempotent"}` + "\n" +
+				`{"Term": "nullipotent", "POS": "a", "Meaning": "having no side effects"}` + "\n\n" +
+				`{"Term": "idempotence", "POS": "a", "Meaning": "producing the same result, however often applied", "explicit": false}` + "\n",
+		},
+	}
+
+	want := []types.DictEntry{
+		{
+			Id:           types.LocalSynsetId("a", "producing the same result, however often applied"),
+			Definitions:  []string{"producing the same result, however often applied"},
+			Examples:     []types.Example{{Text: "retries are safe when idempotent"}},
+			Members:      []string{"idempotent", "idempotence"},
+			PartOfSpeech: "a",
+		},
+		{
+			Id:           types.LocalSynsetId("a", "having no side effects"),
+			Definitions:  []string{"having no side effects"},
+			Members:      []string{"nullipotent"},
+			PartOfSpeech: "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []types.DictEntry
+
+			err := parsers.DecodeGlossary(tt.format, columns)(strings.NewReader(tt.input), func(e types.DictEntry) error {
+				got = append(got, e)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("DecodeGlossary() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("DecodeGlossary() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func Test_DecodeGlossary_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "missing column", input: "word,definition\nidempotent,having no side effects\n", want: `missing column "part_of_speech"`},
+		{name: "part of speech", input: "word,part_of_speech,definition\nidempotent,adj,having no side effects\n", want: "line 2"},
+		{name: "explicit", input: "word,part_of_speech,definition,explicit\nidempotent,a,having no side effects,maybe\n", want: "line 2"},
+		{name: "relation", input: "word,part_of_speech,definition,relations\nidempotent,a,having no side effects,antonym:00001740-a\n", want: `unknown relation type "antonym"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parsers.DecodeGlossary(parsers.CSV, parsers.Columns{})(strings.NewReader(tt.input), func(types.DictEntry) error { return nil })
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DecodeGlossary() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GlossaryWriter(t *testing.T) {
+	synset := types.NewSynsetInput{
+		Id:           "09818957-n",
+		PartOfSpeech: "n",
+		Definitions:  []string{"someone who tries to bring peace by acceding to demands "},
+		Members:      []string{"appeaser"},
+		Examples: []types.Example{
+			{Text: "An appeaser is one who feeds a crocodile--hoping it will eat him last", Source: "Winston Churchill"},
+			{Text: "\"peace for our time\", said the appeaser"},
+		},
+		Relations: []types.Relation{
+			{Source: "09818957-n", Target: "10147935-n", Type: types.Hypernym},
+			{Source: "09818957-n", Target: "pt:10147935-n", Type: types.Similar},
+		},
+	}
+
+	for _, format := range parsers.GlossaryFormats {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			w, err := parsers.NewGlossaryWriter(&buf, format, parsers.Columns{"word": "Term"})
+			if err != nil {
+				t.Fatalf("NewGlossaryWriter() error = %v", err)
+			}
+
+			for _, row := range synset.Glossary() {
+				if err := w.Write(row); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			w.Flush()
+
+			var got []types.DictEntry
+			err = parsers.DecodeGlossary(format, parsers.Columns{"word": "Term"})(&buf, func(e types.DictEntry) error {
+				got = append(got, e)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("DecodeGlossary() error = %v", err)
+			}
+
+			if len(got) != 1 || !reflect.DeepEqual(got[0].Synset().Glossary(), synset.Glossary()) {
+				t.Errorf("round trip = %+v, want %+v", got, synset)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package parsers
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+)
+
+/**
+<LexicalResource xmlns:dc="https://globalwordnet.github.io/schemas/dc/">
+  <Lexicon id="oewn" label="Open English WordNet" language="en" version="2023">
+    <LexicalEntry id="oewn-emergent-s">
+      <Lemma writtenForm="emergent" partOfSpeech="s"/>
+      <Sense id="oewn-emergent__5.00.00.nascent.00" synset="oewn-00003552-s"/>
+    </LexicalEntry>
+    <Synset id="oewn-00003552-s" ili="i10" partOfSpeech="s" members="oewn-emergent-s oewn-emerging-s">
+      <Definition>coming into existence</Definition>
+      <Example>an emergent republic</Example>
+      <SynsetRelation relType="similar" target="oewn-00003356-a"/>
+    </Synset>
+  </Lexicon>
+</LexicalResource>
+*/
+
+// LMFParser reads wordnets in the Global WordNet Association LMF (XML) format,
+// as shipped by Open English WordNet and the Open Multilingual Wordnet.
+type LMFParser struct {
+	loader protocols.LoaderFunc
+	opener protocols.OpenerFunc
+}
+
+var _ protocols.FileParserProtocol = (*LMFParser)(nil)
+
+func NewLMFParser(l protocols.LoaderFunc, o protocols.OpenerFunc) *LMFParser {
+	return &LMFParser{loader: l, opener: o}
+}
+
+func (p *LMFParser) LoadFiles(ctx context.Context, dir string) []fs.DirEntry {
+	files, err := p.loader(dir)
+	if err != nil {
+		return nil
+	}
+	return files
+}
+
+func (p *LMFParser) ParseFile(ctx context.Context, dir string, file fs.DirEntry) (*types.ParsedFile, error) {
+	return collect(ctx, p, dir, file)
+}
+
+func (p *LMFParser) ParseFiles(ctx context.Context, dir string, files []fs.DirEntry, capturer protocols.CaptureFunc) ([]types.ParsedFile, error) {
+	return collectAll(ctx, p, dir, files, capturer)
+}
+
+// StreamFile - decodes the synsets of an LMF file (optionally gzipped) one at a time.
+func (p *LMFParser) StreamFile(ctx context.Context, dir string, file fs.DirEntry) (<-chan types.DictEntry, <-chan error) {
+	open := func() (io.ReadCloser, error) {
+		f, err := p.opener(filepath.Join(dir, file.Name()))
+		if err != nil || !strings.HasSuffix(file.Name(), ".gz") {
+			return f, err
+		}
+
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		return struct {
+			io.Reader
+			io.Closer
+		}{gz, f}, nil
+	}
+
+	return stream(ctx, file.Name(), open, DecodeLMF)
+}
+
+// DecodeLMF - decodes the synsets of an LMF document one at a time.
+//
+// Lexical entries precede synsets in LMF, so only the lemma of every entry is kept
+// in memory; each synset is emitted as soon as its closing tag is read. Identifiers
+// lose their lexicon prefix (i.e oewn-00003552-s becomes 00003552-s), so they line
+// up with the offsets used by the YAML and WNDB formats. Synsets are tagged with the
+// language of their lexicon.
+//
+// Relations that point the other way of a pointer the dictionary stores (i.e hyponym) become
+// that pointer on their target, unless it declares it already. Sense relations (i.e antonym),
+// relation types the dictionary does not store, and inverses whose target was emitted without
+// the pointer they imply are counted as skipped, with the synset they were found on.
+func DecodeLMF(r io.Reader, emit func(types.DictEntry) error) error {
+	decoder := xml.NewDecoder(r)
+
+	var prefix, lang string
+	var lemmas = map[string]string{}   // entry id → written form
+	var senses = map[string][]string{} // synset id → written forms, in document order
+
+	var entry, lemma, sense string
+	var synset *types.DictEntry
+	var members []string
+	var text *strings.Builder
+	var example types.Example
+
+	var skipped = map[string]int{}              // synset id → relations of its senses
+	var pending = map[string][]types.Relation{} // synset id → pointers its inverses imply, for when it is read
+	var declared = map[types.Relation]bool{}    // pointers of the synsets read so far
+	var read = map[string]bool{}                // synsets read so far
+	var held *types.DictEntry                   // the synset read last, emitted after the next one
+
+	strip := func(id string) string { return strings.TrimPrefix(id, prefix) }
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			if held == nil {
+				return nil
+			}
+
+			/* Relations whose synset never came are counted with the synset read last */
+			for _, relations := range pending {
+				held.SkippedRelations += len(relations)
+			}
+			for _, n := range skipped {
+				held.SkippedRelations += n
+			}
+
+			return emit(*held)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			attrs := attributes(t)
+
+			switch t.Name.Local {
+			case "Lexicon":
+				if id := attrs["id"]; id != "" {
+					prefix = id + "-"
+				}
+
+				if lang, err = types.NormalizeLanguage(attrs["language"]); err != nil {
+					return err
+				}
+			case "LexicalEntry":
+				entry, lemma = attrs["id"], ""
+			case "Lemma":
+				lemma = attrs["writtenForm"]
+				lemmas[entry] = lemma
+			case "Sense":
+				if sense = strip(attrs["synset"]); sense != "" {
+					senses[sense] = append(senses[sense], lemma)
+				}
+			case "SenseRelation":
+				if sense != "" {
+					skipped[sense]++
+				}
+			case "Synset":
+				ili := attrs["ili"]
+				if ili == "in" {
+					ili = "" // proposed, not yet part of the interlingual index
+				}
+
+				synset = &types.DictEntry{Id: strip(attrs["id"]), PartOfSpeech: attrs["partOfSpeech"], Identifier: ili, Language: lang, Lexfile: attrs["lexfile"]}
+				members = strings.Fields(attrs["members"])
+			case "Definition", "Example":
+				if synset != nil {
+					text = &strings.Builder{}
+					example = types.Example{Source: attrs["source"]}
+				}
+			case "SynsetRelation":
+				if synset == nil {
+					continue
+				}
+
+				kind, target := types.RelationType(attrs["relType"]), strip(attrs["target"])
+				forward, inverse := types.InverseRelations[kind]
+
+				switch {
+				case synset.AddRelation(kind, target):
+					declared[types.Relation{Source: synset.Id, Target: target, Type: kind}] = true
+				case inverse && !read[target]:
+					pending[target] = append(pending[target], types.Relation{Source: target, Target: synset.Id, Type: forward})
+				case inverse && declared[types.Relation{Source: target, Target: synset.Id, Type: forward}]:
+				default:
+					synset.SkippedRelations++
+				}
+			}
+		case xml.CharData:
+			if text != nil {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "Sense":
+				sense = ""
+			case "Definition":
+				if synset != nil && text != nil {
+					synset.Definitions = append(synset.Definitions, strings.TrimSpace(text.String()))
+				}
+				text = nil
+			case "Example":
+				if synset != nil && text != nil {
+					example.Text = strings.TrimSpace(text.String())
+					synset.Examples = append(synset.Examples, example)
+				}
+				text = nil
+			case "Synset":
+				if synset == nil {
+					continue
+				}
+
+				if len(members) > 0 {
+					for _, member := range members {
+						synset.Members = append(synset.Members, lemmas[member])
+					}
+				} else {
+					synset.Members = senses[synset.Id]
+				}
+
+				for _, relation := range pending[synset.Id] {
+					if !synset.HasRelation(relation.Type, relation.Target) && synset.AddRelation(relation.Type, relation.Target) {
+						declared[relation] = true
+					}
+				}
+
+				synset.SkippedRelations += skipped[synset.Id]
+				read[synset.Id] = true
+
+				if held != nil {
+					if err := emit(*held); err != nil {
+						return err
+					}
+				}
+
+				delete(senses, synset.Id)
+				delete(pending, synset.Id)
+				delete(skipped, synset.Id)
+				held, synset = synset, nil
+			}
+		}
+	}
+}
+
+func attributes(e xml.StartElement) map[string]string {
+	var attrs = make(map[string]string, len(e.Attr))
+
+	for _, a := range e.Attr {
+		attrs[a.Name.Local] = a.Value
+	}
+
+	return attrs
+}
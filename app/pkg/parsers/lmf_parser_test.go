@@ -0,0 +1,201 @@
+package parsers_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/parsers"
+)
+
+const lmfSample = `<?xml version="1.0" encoding="UTF-8"?>
+<LexicalResource xmlns:dc="https://globalwordnet.github.io/schemas/dc/">
+  <Lexicon id="oewn" label="Open English WordNet" language="en" email="" license="" version="2023">
+    <LexicalEntry id="oewn-emergent-s">
+      <Lemma writtenForm="emergent" partOfSpeech="s"/>
+      <Sense id="oewn-emergent__5.00.00.nascent.00" synset="oewn-00003552-s"/>
+    </LexicalEntry>
+    <LexicalEntry id="oewn-emerging-s">
+      <Lemma writtenForm="emerging" partOfSpeech="s"/>
+      <Sense id="oewn-emerging__5.00.00.nascent.00" synset="oewn-00003552-s"/>
+    </LexicalEntry>
+    <LexicalEntry id="oewn-appeaser-n">
+      <Lemma writtenForm="appeaser" partOfSpeech="n"/>
+      <Sense id="oewn-appeaser__1.18.00.." synset="oewn-09818957-n"/>
+    </LexicalEntry>
+    <Synset id="oewn-00003552-s" ili="i10" partOfSpeech="s" members="oewn-emergent-s oewn-emerging-s">
+      <Definition>coming into existence</Definition>
+      <Example>an emergent republic</Example>
+      <SynsetRelation relType="similar" target="oewn-00003356-a"/>
+    </Synset>
+    <Synset id="oewn-09818957-n" ili="i88255" partOfSpeech="n">
+      <Definition>someone who tries to bring peace by acceding to demands</Definition>
+      <Example dc:source="Winston Churchill">An appeaser is one who feeds a crocodile--hoping it will eat him last</Example>
+      <SynsetRelation relType="hyponym" target="oewn-09999999-n"/>
+    </Synset>
+  </Lexicon>
+</LexicalResource>
+`
+
+func Test_DecodeLMF(t *testing.T) {
+	var got []types.DictEntry
+
+	err := parsers.DecodeLMF(strings.NewReader(lmfSample), func(e types.DictEntry) error {
+		got = append(got, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeLMF() error = %v", err)
+	}
+
+	var want []types.DictEntry
+	parsers.DecodeEntries(strings.NewReader(sample), func(e types.DictEntry) error {
+		e.Language = "en"
+		want = append(want, e)
+		return nil
+	})
+
+	/* The hyponym of the appeaser is not in the document */
+	want[1].SkippedRelations = 1
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeLMF() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_DecodeLMF_Relations(t *testing.T) {
+	const relations = `<LexicalResource>
+  <Lexicon id="oewn" label="Open English WordNet" language="en" version="2023">
+    <LexicalEntry id="oewn-dog-n">
+      <Lemma writtenForm="dog" partOfSpeech="n"/>
+      <Sense id="oewn-dog__1.05.00.." synset="oewn-02084071-n">
+        <SenseRelation relType="derivation" target="oewn-dog__2.38.00.."/>
+      </Sense>
+    </LexicalEntry>
+    <Synset id="oewn-02083346-n" partOfSpeech="n" members="oewn-canine-n">
+      <Definition>any of various fissiped mammals</Definition>
+      <SynsetRelation relType="hyponym" target="oewn-02084071-n"/>
+      <SynsetRelation relType="hyponym" target="oewn-02114100-n"/>
+    </Synset>
+    <Synset id="oewn-02084071-n" partOfSpeech="n">
+      <Definition>a member of the genus Canis</Definition>
+      <SynsetRelation relType="hypernym" target="oewn-02083346-n"/>
+      <SynsetRelation relType="holo_member" target="oewn-02083863-n"/>
+      <SynsetRelation relType="other" target="oewn-02083346-n"/>
+    </Synset>
+    <Synset id="oewn-02114100-n" partOfSpeech="n">
+      <Definition>any of various wild canines</Definition>
+      <SynsetRelation relType="hyponym" target="oewn-02083346-n"/>
+    </Synset>
+  </Lexicon>
+</LexicalResource>`
+
+	var got = map[string]types.DictEntry{}
+
+	err := parsers.DecodeLMF(strings.NewReader(relations), func(e types.DictEntry) error {
+		got[e.Id] = e
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeLMF() error = %v", err)
+	}
+
+	tests := []struct {
+		id       string
+		hypernym []string
+		skipped  int
+	}{
+		{id: "02083346-n", hypernym: nil, skipped: 0},
+		// Declared, and implied by its hypernym; the sense derivation and the other relation are skipped
+		{id: "02084071-n", hypernym: []string{"02083346-n"}, skipped: 2},
+		// Implied by its hypernym; its own hyponym was read without the hypernym it implies,
+		// and the synset of the holonym never comes
+		{id: "02114100-n", hypernym: []string{"02083346-n"}, skipped: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			entry := got[tt.id]
+
+			if !reflect.DeepEqual(entry.Hypernym, tt.hypernym) {
+				t.Errorf("DecodeLMF() hypernym of %s = %v, want %v", tt.id, entry.Hypernym, tt.hypernym)
+			}
+
+			if entry.SkippedRelations != tt.skipped {
+				t.Errorf("DecodeLMF() skipped %d relations of %s, want %d", entry.SkippedRelations, tt.id, tt.skipped)
+			}
+		})
+	}
+}
+
+func Test_DecodeLMF_Language(t *testing.T) {
+	const portuguese = `<LexicalResource>
+  <Lexicon id="omw-pt" label="OpenWN-PT" language="pt" version="1.4">
+    <LexicalEntry id="omw-pt-emergente-s">
+      <Lemma writtenForm="emergente" partOfSpeech="s"/>
+      <Sense id="omw-pt-00003552-s-emergente" synset="omw-pt-00003552-s"/>
+    </LexicalEntry>
+    <Synset id="omw-pt-00003552-s" ili="i10" partOfSpeech="s" lexfile="adj.all"/>
+  </Lexicon>
+</LexicalResource>`
+
+	var got []types.DictEntry
+
+	err := parsers.DecodeLMF(strings.NewReader(portuguese), func(e types.DictEntry) error {
+		got = append(got, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeLMF() error = %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("DecodeLMF() = %d synsets, want 1", len(got))
+	}
+
+	synset := got[0].Synset()
+	if synset.Id != "pt:00003552-s" || synset.Language != "pt" || synset.ILI != "i10" || synset.Lexfile != "adj.all" || !reflect.DeepEqual(synset.Members, []string{"emergente"}) {
+		t.Errorf("DecodeLMF() = %+v, want pt:00003552-s (pt, i10, adj.all) with emergente", synset)
+	}
+}
+
+func Test_AutoParser(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(lmfSample))
+	w.Close()
+
+	fsys := fstest.MapFS{
+		"english/adj.all.yaml": {Data: []byte(sample)},
+		"english/oewn.xml.gz":  {Data: gz.Bytes()},
+		"english/README.md":    {Data: []byte("# English WordNet")},
+	}
+
+	parser := parsers.NewAutoParser(
+		func(dir string) ([]fs.DirEntry, error) { return fs.ReadDir(fsys, dir) },
+		func(name string) (io.ReadCloser, error) { return fsys.Open(name) },
+	)
+
+	files := parser.LoadFiles(context.Background(), "english")
+	if len(files) != 2 {
+		t.Fatalf("LoadFiles() = %v files, want 2", len(files))
+	}
+
+	for _, file := range files {
+		parsed, err := parser.ParseFile(context.Background(), "english", file)
+		if err != nil {
+			t.Fatalf("ParseFile(%v) error = %v", file.Name(), err)
+		}
+
+		if len(parsed.Data) != 2 {
+			t.Errorf("ParseFile(%v) = %d synsets, want 2", file.Name(), len(parsed.Data))
+		}
+	}
+}
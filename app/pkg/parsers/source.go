@@ -0,0 +1,176 @@
+package parsers
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+)
+
+// Source - dictionary files on the local filesystem, exposed as a file system
+// whose root holds every file to be parsed.
+//
+// A source may be a directory (searched recursively), a single file, or a
+// .zip, .tar.gz or .tgz archive.
+type Source struct {
+	fsys  fs.FS
+	only  string
+	close func() error
+}
+
+// OpenSource - opens the directory, file or archive at the given path.
+//
+// Archives are read in place (.zip) or extracted to a temporary directory (.tar.gz),
+// which is removed when the source is closed.
+func OpenSource(p string) (*Source, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	noop := func() error { return nil }
+
+	switch name := strings.ToLower(info.Name()); {
+	case info.IsDir():
+		return &Source{fsys: os.DirFS(p), close: noop}, nil
+	case strings.HasSuffix(name, ".zip"):
+		r, err := zip.OpenReader(p)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Source{fsys: r, close: r.Close}, nil
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		dir, err := extractTarball(p)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Source{fsys: os.DirFS(dir), close: func() error { return os.RemoveAll(dir) }}, nil
+	}
+
+	return &Source{fsys: os.DirFS(filepath.Dir(p)), only: info.Name(), close: noop}, nil
+}
+
+// SingleFile - whether the source is a single file, rather than a directory or an archive.
+func (s *Source) SingleFile() bool { return s.only != "" }
+
+// Loader - lists every regular file of the source, regardless of the requested directory.
+//
+// Entries are named after their path from the root of the source (i.e yaml/noun.animal.yaml),
+// so they can be joined back with the root directory (".") when opened.
+func (s *Source) Loader() protocols.LoaderFunc {
+	return func(string) ([]fs.DirEntry, error) {
+		var files []fs.DirEntry
+
+		err := fs.WalkDir(s.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+				return nil
+			}
+
+			if s.only != "" && p != s.only {
+				return nil
+			}
+
+			files = append(files, entry{DirEntry: d, path: p})
+			return nil
+		})
+
+		return files, err
+	}
+}
+
+func (s *Source) Reader() protocols.ReaderFunc {
+	return func(name string) ([]byte, error) { return fs.ReadFile(s.fsys, path.Clean(name)) }
+}
+
+func (s *Source) Opener() protocols.OpenerFunc {
+	return func(name string) (io.ReadCloser, error) { return s.fsys.Open(path.Clean(name)) }
+}
+
+func (s *Source) Close() error { return s.close() }
+
+// entry - a file named after its path from the root of a source.
+type entry struct {
+	fs.DirEntry
+	path string
+}
+
+func (e entry) Name() string { return e.path }
+
+func extractTarball(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return "", err
+	}
+	defer gz.Close()
+
+	dir, err := os.MkdirTemp("", "redic-import-")
+	if err != nil {
+		return "", err
+	}
+
+	extract := func() error {
+		r := tar.NewReader(gz)
+
+		for {
+			header, err := r.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if header.Typeflag != tar.TypeReg {
+				continue
+			}
+
+			name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+			if !fs.ValidPath(name) {
+				return fmt.Errorf("%s: unsafe path in archive: %s", p, header.Name)
+			}
+
+			dst := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+				return err
+			}
+
+			out, err := os.Create(dst)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(out, r)
+			out.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := extract(); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,81 @@
+package parsers_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/oleoneto/redic/app/pkg/parsers"
+)
+
+func Test_OpenSource(t *testing.T) {
+	dir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(dir, "wordnet", "yaml"), 0o755)
+	os.WriteFile(filepath.Join(dir, "wordnet", "yaml", "adj.all.yaml"), []byte(sample), 0o644)
+
+	tarball := filepath.Join(t.TempDir(), "wordnet.tar.gz")
+	func() {
+		f, _ := os.Create(tarball)
+		defer f.Close()
+
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+
+		w := tar.NewWriter(gz)
+		defer w.Close()
+
+		w.WriteHeader(&tar.Header{Name: "./yaml/adj.all.yaml", Mode: 0o644, Size: int64(len(sample)), Typeflag: tar.TypeReg})
+		w.Write([]byte(sample))
+	}()
+
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{name: "directory", path: dir, want: []string{"wordnet/yaml/adj.all.yaml"}},
+		{name: "file", path: filepath.Join(dir, "wordnet", "yaml", "adj.all.yaml"), want: []string{"adj.all.yaml"}},
+		{name: "tarball", path: tarball, want: []string{"yaml/adj.all.yaml"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := parsers.OpenSource(tt.path)
+			if err != nil {
+				t.Fatalf("OpenSource() error = %v", err)
+			}
+			defer source.Close()
+
+			parser := parsers.StreamingParser(source.Loader(), source.Opener())
+
+			var names []string
+			var synsets int
+
+			for _, file := range parser.LoadFiles(context.Background(), ".") {
+				names = append(names, file.Name())
+
+				entries, errs := parser.StreamFile(context.Background(), ".", file)
+				for range entries {
+					synsets++
+				}
+
+				if err := <-errs; err != nil {
+					t.Fatalf("StreamFile() error = %v", err)
+				}
+			}
+
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("LoadFiles() = %v, want %v", names, tt.want)
+			}
+
+			if synsets != 2 {
+				t.Errorf("StreamFile() = %d synsets, want 2", synsets)
+			}
+		})
+	}
+}
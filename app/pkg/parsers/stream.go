@@ -0,0 +1,156 @@
+package parsers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/sirupsen/logrus"
+)
+
+// Decoder - decodes every synset of a dictionary file, calling emit for each one.
+// Decoding stops at the first error returned by emit.
+type Decoder func(r io.Reader, emit func(types.DictEntry) error) error
+
+type streamer interface {
+	StreamFile(context.Context, string, fs.DirEntry) (<-chan types.DictEntry, <-chan error)
+}
+
+// stream - decodes the opened file on its own goroutine.
+//
+// The entries channel is closed once the file is exhausted, the context is cancelled,
+// or decoding fails. The error channel then yields at most one error.
+func stream(ctx context.Context, name string, open func() (io.ReadCloser, error), decode Decoder) (<-chan types.DictEntry, <-chan error) {
+	entries := make(chan types.DictEntry)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(entries)
+
+		r, err := open()
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer r.Close()
+
+		err = decode(r, func(entry types.DictEntry) error {
+			entry.File = name
+			if entry.Lexfile == "" {
+				entry.Lexfile = types.LexfileOf(name)
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case entries <- entry:
+				return nil
+			}
+		})
+		if err != nil {
+			errs <- fmt.Errorf("%s: %w", name, err)
+		}
+	}()
+
+	return entries, errs
+}
+
+// streamAll - decodes the synsets of every file in order, one at a time.
+func streamAll(ctx context.Context, s streamer, dir string, files []fs.DirEntry) (<-chan types.DictEntry, <-chan error) {
+	entries := make(chan types.DictEntry)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(entries)
+
+		for _, file := range files {
+			fileEntries, fileErrs := s.StreamFile(ctx, dir, file)
+
+			for entry := range fileEntries {
+				select {
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				case entries <- entry:
+				}
+			}
+
+			if err := <-fileErrs; err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+
+	return entries, errs
+}
+
+// collect - decodes a whole file into memory.
+func collect(ctx context.Context, s streamer, dir string, file fs.DirEntry) (*types.ParsedFile, error) {
+	var pf = map[string]types.DictEntry{}
+
+	entries, errs := s.StreamFile(ctx, dir, file)
+	for entry := range entries {
+		pf[entry.Id] = entry
+	}
+
+	if err := <-errs; err != nil {
+		logrus.Errorln(file.Name(), "Error:", err)
+		return nil, err
+	}
+
+	return &types.ParsedFile{Data: pf, Name: file.Name()}, nil
+}
+
+func collectAll(ctx context.Context, s protocols.FileParserProtocol, dir string, files []fs.DirEntry, capturer protocols.CaptureFunc) ([]types.ParsedFile, error) {
+	var entries = []types.ParsedFile{}
+
+	for _, de := range files {
+		parsedFile, err := s.ParseFile(ctx, dir, de)
+		if err != nil || parsedFile == nil {
+			return nil, err
+		}
+
+		entries = append(entries, *parsedFile)
+
+		go capturer(parsedFile)
+	}
+
+	return entries, nil
+}
+
+// closed - returns exhausted channels, for files that yield no synsets.
+func closed() (<-chan types.DictEntry, <-chan error) {
+	entries := make(chan types.DictEntry)
+	errs := make(chan error)
+
+	close(entries)
+	close(errs)
+
+	return entries, errs
+}
+
+// Checksum - returns a function that hashes (sha256) the contents of a file opened with o.
+func Checksum(o protocols.OpenerFunc) func(dir string, file fs.DirEntry) (string, error) {
+	return func(dir string, file fs.DirEntry) (string, error) {
+		f, err := o(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%x", h.Sum(nil)), nil
+	}
+}
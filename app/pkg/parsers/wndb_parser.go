@@ -0,0 +1,350 @@
+package parsers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/morphy"
+)
+
+/**
+Each line of a data file (data.noun, data.verb, data.adj, data.adv) is one synset:
+
+	synset_offset lex_filenum ss_type w_cnt word lex_id [word lex_id...] p_cnt [ptr...] [frames...] | gloss
+
+	00003552 00 s 02 emergent 0 emerging 0 001 & 00003356 a 0000 | coming into existence; "an emergent republic"
+
+Lines starting with two spaces hold the license, which names the release:
+
+	  29 WordNet 3.0 Copyright 2006 by Princeton University.  All rights reserved.
+
+The interlingual index of each synset comes from the ILI project's mapping of the release
+(i.e ili-map-pwn30.tab), when it sits next to the data files:
+
+	i1	00001740-a
+*/
+
+// WNDBParser reads the data files of the Princeton WordNet database (WNDB) format,
+// as distributed with WordNet 3.0 and 3.1. Index files (index.pos) list the synsets
+// of each word, which the data files already hold, so LoadFiles leaves them out.
+// Exception lists (*.exc) go to morphy instead; see ExceptionLists.
+type WNDBParser struct {
+	loader protocols.LoaderFunc
+	opener protocols.OpenerFunc
+
+	mu  sync.Mutex
+	ili map[string]map[string]string // release → offset-type → ILI
+}
+
+var _ protocols.FileParserProtocol = (*WNDBParser)(nil)
+
+func NewWNDBParser(l protocols.LoaderFunc, o protocols.OpenerFunc) *WNDBParser {
+	return &WNDBParser{loader: l, opener: o, ili: map[string]map[string]string{}}
+}
+
+// pointerSymbols - WNDB pointer symbols of the relations redic stores.
+// Inverse pointers (hyponyms, holonyms, domain members) are implied by these
+// and lexical-only pointers (antonyms, pertainyms, derivations) are not kept.
+var pointerSymbols = map[string]types.RelationType{
+	"@":  types.Hypernym,
+	"@i": types.InstanceHypernym,
+	"%m": types.MeroMember,
+	"%p": types.MeroPart,
+	"%s": types.MeroSubstance,
+	"&":  types.Similar,
+	"^":  types.Also,
+	"=":  types.Attribute,
+	">":  types.Causes,
+	"*":  types.Entails,
+	";c": types.DomainTopic,
+	";r": types.DomainRegion,
+	";u": types.Exemplifies,
+}
+
+// Syntactic markers of adjectives, i.e galore(ip)
+var adjectiveMarker = regexp.MustCompile(`\((a|p|ip)\)$`)
+
+// Release named in the license, i.e WordNet 3.0
+var wndbRelease = regexp.MustCompile(`WordNet (\d+)\.(\d+)`)
+
+// Namespace of synsets whose license does not name a release.
+const wndbNamespace = "wn"
+
+func (p *WNDBParser) LoadFiles(ctx context.Context, dir string) []fs.DirEntry {
+	files, err := p.loader(dir)
+	if err != nil {
+		return nil
+	}
+
+	var data []fs.DirEntry
+	for _, file := range files {
+		if format, ok := DetectFormat(file.Name()); ok && format == WNDB {
+			data = append(data, file)
+		}
+	}
+
+	return data
+}
+
+func (p *WNDBParser) ParseFile(ctx context.Context, dir string, file fs.DirEntry) (*types.ParsedFile, error) {
+	return collect(ctx, p, dir, file)
+}
+
+func (p *WNDBParser) ParseFiles(ctx context.Context, dir string, files []fs.DirEntry, capturer protocols.CaptureFunc) ([]types.ParsedFile, error) {
+	return collectAll(ctx, p, dir, files, capturer)
+}
+
+// StreamFile - decodes the synsets of a WNDB data file one at a time,
+// linking them to the interlingual index when the release's ILI mapping is found.
+func (p *WNDBParser) StreamFile(ctx context.Context, dir string, file fs.DirEntry) (<-chan types.DictEntry, <-chan error) {
+	open := func() (io.ReadCloser, error) { return p.opener(filepath.Join(dir, file.Name())) }
+
+	decode := func(r io.Reader, emit func(types.DictEntry) error) error {
+		return DecodeWNDB(r, func(entry types.DictEntry) error {
+			release, offset, _ := strings.Cut(entry.Id, ":")
+			entry.Identifier = p.interlingual(filepath.Join(dir, path.Dir(file.Name())), release)[offset]
+
+			return emit(entry)
+		})
+	}
+
+	return stream(ctx, file.Name(), open, decode)
+}
+
+// interlingual - the ILI mapping of the release (i.e ili-map-pwn30.tab) found in the directory,
+// read once. Satellites are listed as adjectives in some mappings, so they are kept under both.
+func (p *WNDBParser) interlingual(dir, release string) map[string]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ili, ok := p.ili[release]; ok {
+		return ili
+	}
+
+	var ili = map[string]string{}
+	p.ili[release] = ili
+
+	f, err := p.opener(filepath.Join(dir, "ili-map-p"+release+".tab"))
+	if err != nil {
+		return ili
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		ili[fields[1]] = fields[0]
+		if offset, ok := strings.CutSuffix(fields[1], "-a"); ok {
+			ili[offset+"-s"] = fields[0]
+		}
+	}
+
+	return ili
+}
+
+// DecodeWNDB - decodes a WNDB data file one synset at a time.
+//
+// Synsets are identified by their offset and type, namespaced by the release named
+// in the license (i.e wn30:00003552-s), so they never take the place of the YAML and LMF
+// synsets of Open English WordNet, which reuse those offsets. Words lose their underscores
+// and adjective markers.
+func DecodeWNDB(r io.Reader, emit func(types.DictEntry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var line int
+	var namespace = wndbNamespace
+
+	for scanner.Scan() {
+		line++
+
+		text := scanner.Text()
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "  ") {
+			if m := wndbRelease.FindStringSubmatch(text); m != nil {
+				namespace = wndbNamespace + m[1] + m[2]
+			}
+			continue
+		}
+
+		entry, err := decodeSynsetLine(text, namespace)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if err := emit(entry); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func decodeSynsetLine(line, namespace string) (types.DictEntry, error) {
+	var entry types.DictEntry
+
+	data, gloss, _ := strings.Cut(line, " | ")
+	fields := strings.Fields(data)
+
+	// Reads the next field, failing once the line is exhausted.
+	var i int
+	next := func() (string, error) {
+		if i >= len(fields) {
+			return "", fmt.Errorf("synset %v is truncated", entry.Id)
+		}
+		i++
+		return fields[i-1], nil
+	}
+
+	count := func(base int) (int, error) {
+		field, err := next()
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.ParseInt(field, base, 32)
+		return int(n), err
+	}
+
+	if len(fields) < 4 {
+		return entry, fmt.Errorf("malformed synset: %q", line)
+	}
+
+	entry.Id = namespace + ":" + fields[0] + "-" + fields[2]
+	entry.PartOfSpeech = fields[2]
+	i = 3
+
+	if lexfile, err := strconv.Atoi(fields[1]); err == nil && lexfile >= 0 && lexfile < len(types.Lexnames) {
+		entry.Lexfile = types.Lexnames[lexfile]
+	}
+
+	words, err := count(16)
+	if err != nil {
+		return entry, err
+	}
+
+	for range words {
+		word, err := next()
+		if err != nil {
+			return entry, err
+		}
+		if _, err := next(); err != nil { // lex_id
+			return entry, err
+		}
+
+		word = adjectiveMarker.ReplaceAllString(word, "")
+		entry.Members = append(entry.Members, strings.ReplaceAll(word, "_", " "))
+	}
+
+	pointers, err := count(10)
+	if err != nil {
+		return entry, err
+	}
+
+	for range pointers {
+		var ptr [4]string // symbol, offset, pos, source/target
+		for j := range ptr {
+			if ptr[j], err = next(); err != nil {
+				return entry, err
+			}
+		}
+
+		if kind, ok := pointerSymbols[ptr[0]]; ok {
+			entry.AddRelation(kind, namespace+":"+ptr[1]+"-"+ptr[2])
+		}
+	}
+
+	entry.Definitions, entry.Examples = splitGloss(gloss)
+
+	return entry, nil
+}
+
+// splitGloss - separates the definition of a gloss from its quoted examples.
+//
+//	coming into existence; "an emergent republic"
+//	"to be or not to be" - Shakespeare
+func splitGloss(gloss string) ([]string, []types.Example) {
+	var definitions []string
+	var examples []types.Example
+
+	var parts []string
+	var quoted bool
+	var start int
+
+	for i, r := range gloss {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ';' && !quoted:
+			parts = append(parts, gloss[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, gloss[start:])
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+
+		switch {
+		case part == "":
+		case strings.HasPrefix(part, `"`):
+			text, source := part[1:], ""
+			if end := strings.LastIndex(text, `"`); end >= 0 {
+				text, source = text[:end], strings.TrimLeft(text[end+1:], " -")
+			}
+			examples = append(examples, types.Example{Text: text, Source: source})
+		default:
+			definitions = append(definitions, part)
+		}
+	}
+
+	if len(definitions) > 1 {
+		definitions = []string{strings.Join(definitions, types.DefinitionSeparator)}
+	}
+
+	return definitions, examples
+}
+
+// isWNDB - whether the file is one of the four WNDB data files.
+func isWNDB(name string) bool {
+	switch path.Base(name) {
+	case "data.noun", "data.verb", "data.adj", "data.adv":
+		return true
+	}
+	return false
+}
+
+// ExceptionLists - the exception lists of a WNDB release (noun.exc, verb.exc, adj.exc, adv.exc)
+// among the files of the directory, for morphy to learn the irregular forms of the release.
+func ExceptionLists(loader protocols.LoaderFunc, dir string) []fs.DirEntry {
+	files, err := loader(dir)
+	if err != nil {
+		return nil
+	}
+
+	var lists []fs.DirEntry
+	for _, file := range files {
+		if _, ok := morphy.ExceptionList(file.Name()); ok {
+			lists = append(lists, file)
+		}
+	}
+
+	return lists
+}
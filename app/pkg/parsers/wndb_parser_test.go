@@ -0,0 +1,139 @@
+package parsers_test
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/parsers"
+)
+
+const wndbSample = `  1 This software and database is being provided to you, the LICENSEE, by  
+  2 Princeton University under the following license.  
+  3 WordNet 3.0 Copyright 2006 by Princeton University.  All rights reserved.  
+00003552 00 s 02 emergent 0 emerging 0 001 & 00003356 a 0000 | coming into existence; "an emergent republic"  
+01514619 00 a 01 galore(ip) 0 001 ^ 00015247 a 0000 | in great numbers; in abundance; "they had bread galore"  
+09818957 18 n 01 appeaser 0 002 @ 10383430 n 0000 ~ 09906204 n 0000 | someone who tries to bring peace by acceding to demands; "An appeaser is one who feeds a crocodile--hoping it will eat him last; as Churchill said" - Winston Churchill  
+`
+
+func Test_DecodeWNDB(t *testing.T) {
+	var got []types.DictEntry
+
+	err := parsers.DecodeWNDB(strings.NewReader(wndbSample), func(e types.DictEntry) error {
+		got = append(got, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeWNDB() error = %v", err)
+	}
+
+	want := []types.DictEntry{
+		{
+			Id:           "wn30:00003552-s",
+			Definitions:  []string{"coming into existence"},
+			Examples:     []types.Example{{Text: "an emergent republic"}},
+			Members:      []string{"emergent", "emerging"},
+			PartOfSpeech: "s",
+			Lexfile:      "adj.all",
+			Similar:      []string{"wn30:00003356-a"},
+		},
+		{
+			Id:           "wn30:01514619-a",
+			Definitions:  []string{"in great numbers; in abundance"},
+			Examples:     []types.Example{{Text: "they had bread galore"}},
+			Members:      []string{"galore"},
+			PartOfSpeech: "a",
+			Lexfile:      "adj.all",
+			Also:         []string{"wn30:00015247-a"},
+		},
+		{
+			Id:          "wn30:09818957-n",
+			Definitions: []string{"someone who tries to bring peace by acceding to demands"},
+			Examples: []types.Example{{
+				Text:   "An appeaser is one who feeds a crocodile--hoping it will eat him last; as Churchill said",
+				Source: "Winston Churchill",
+			}},
+			Members:      []string{"appeaser"},
+			PartOfSpeech: "n",
+			Lexfile:      "noun.person",
+			Hypernym:     []string{"wn30:10383430-n"},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeWNDB() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_DecodeWNDB_Error(t *testing.T) {
+	err := parsers.DecodeWNDB(strings.NewReader("00003552 00 s 02 emergent 0 | coming into existence\n"), func(types.DictEntry) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("DecodeWNDB() error = %v, want an error at line 1", err)
+	}
+}
+
+func Test_DecodeWNDB_Namespace(t *testing.T) {
+	tests := []struct {
+		license string
+		want    string
+	}{
+		{"  1 WordNet 3.1 Copyright 2011 by Princeton University.\n", "wn31:00003552-s"},
+		{"  1 This software and database is being provided to you\n", "wn:00003552-s"},
+		{"", "wn:00003552-s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			var got []string
+
+			err := parsers.DecodeWNDB(strings.NewReader(tt.license+"00003552 00 s 01 emergent 0 000 | coming into existence\n"), func(e types.DictEntry) error {
+				got = append(got, e.Id)
+				return nil
+			})
+			if err != nil || !reflect.DeepEqual(got, []string{tt.want}) {
+				t.Errorf("DecodeWNDB() = %v, %v, want [%v]", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WNDBParser(t *testing.T) {
+	fsys := fstest.MapFS{
+		"dict/data.adj":          {Data: []byte(wndbSample)},
+		"dict/ili-map-pwn30.tab": {Data: []byte("i10\t00003552-a\ni11\t01514619-a\n")},
+		"dict/adj.exc":           {Data: []byte("galorer galore\n")},
+		"dict/index.adj":         {Data: []byte("galore a 1 1 ^ 1 0 01514619\n")},
+		"dict/ili-map-pwn31.tab": {Data: []byte("i12\t00003552-a\n")},
+	}
+
+	loader := func(dir string) ([]fs.DirEntry, error) { return fs.ReadDir(fsys, dir) }
+	parser := parsers.NewWNDBParser(loader, func(name string) (io.ReadCloser, error) { return fsys.Open(name) })
+
+	files := parser.LoadFiles(context.Background(), "dict")
+	if len(files) != 1 {
+		t.Fatalf("LoadFiles() = %v files, want 1", len(files))
+	}
+
+	parsed, err := parser.ParseFile(context.Background(), "dict", files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, synset := range parsed.Data {
+		got = append(got, synset.Id+"="+synset.Identifier)
+	}
+
+	if want := []string{"wn30:00003552-s=i10", "wn30:01514619-a=i11", "wn30:09818957-n="}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFile() = %v, want %v", got, want)
+	}
+
+	if lists := parsers.ExceptionLists(loader, "dict"); len(lists) != 1 || lists[0].Name() != "adj.exc" {
+		t.Errorf("ExceptionLists() = %v, want [adj.exc]", lists)
+	}
+}
@@ -0,0 +1,101 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Longest line accepted by the decoder. Glosses are folded well below this.
+const maxLineSize = 1 << 20
+
+// DecodeEntries - decodes a dictionary file one synset at a time.
+//
+// Dictionary files are a single mapping whose top-level keys are synset ids:
+//
+//	00003552-s:
+//	  definition:
+//	  - coming into existence
+//	02057872-a:
+//	  ...
+//
+// Rather than unmarshalling the whole mapping, the input is split at every
+// top-level key and each chunk is decoded on its own, so memory use does not
+// grow with the size of the file. Decoding stops at the first error returned by emit.
+func DecodeEntries(r io.Reader, emit func(types.DictEntry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var chunk bytes.Buffer
+	var line, start int
+
+	flush := func() error {
+		if chunk.Len() == 0 {
+			return nil
+		}
+		defer chunk.Reset()
+
+		var entries map[string]types.DictEntry
+		if err := yaml.Unmarshal(chunk.Bytes(), &entries); err != nil {
+			return fmt.Errorf("entry at line %d: %w", start, err)
+		}
+
+		for key, entry := range entries {
+			entry.Id = key
+			if err := emit(entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for scanner.Scan() {
+		line++
+		text := scanner.Bytes()
+
+		if isDocumentMarker(text) {
+			if err := flush(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if isTopLevelKey(text) {
+			if err := flush(); err != nil {
+				return err
+			}
+			start = line
+		}
+
+		chunk.Write(text)
+		chunk.WriteByte('\n')
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("line %d: %w", line+1, err)
+	}
+
+	return flush()
+}
+
+func isDocumentMarker(line []byte) bool {
+	return bytes.Equal(line, []byte("---")) || bytes.Equal(line, []byte("..."))
+}
+
+func isTopLevelKey(line []byte) bool {
+	if len(line) == 0 {
+		return false
+	}
+
+	switch line[0] {
+	case ' ', '\t', '-', '#':
+		return false
+	}
+
+	return bytes.Contains(line, []byte(":"))
+}
@@ -0,0 +1,75 @@
+// Package ranking orders search matches alike in every dictionary backend:
+// by the relevance the backend computes, blended with lexical priors.
+//
+// Usage:
+//
+//	terms := []string{"domestic*", "animal"} // as search.Terms gives them
+//	score := ranking.Score(types.DefaultRanking, terms, ranking.Match{Relevance: 9.2, Senses: 7, Word: "dog", Gloss: "…"})
+package ranking
+
+import (
+	"strings"
+
+	"github.com/oleoneto/redic/app/domain/types"
+)
+
+// Candidates - how many of the most relevant matches are ranked again with priors at first.
+// Priors only reorder matches of comparable relevance, so the least relevant ones
+// need not be ranked at all, unless a page reaches down to them (see Priors).
+const Candidates = 1000
+
+// Match - what a search match is ranked by.
+type Match struct {
+	Relevance float64 // field-weighted BM25, higher being better
+	Position  int     // of its word among the members of its synset, from 0
+	Senses    int     // how many synsets its word is a member of
+	Word      string
+	Gloss     string
+}
+
+// Score - ranks a match of a query, given the terms the query searches for.
+func Score(weights types.Ranking, terms []string, m Match) types.Score {
+	var score = types.Score{
+		Relevance:  m.Relevance,
+		SenseOrder: weights.SenseOrder / float64(1+m.Position),
+		Headword:   weights.Headword * Coverage(terms, m.Word),
+		Coverage:   weights.Coverage * Coverage(terms, m.Gloss),
+	}
+
+	if m.Senses > 0 {
+		score.Polysemy = weights.Polysemy * (1 - 1/float64(m.Senses))
+	}
+
+	score.Total = score.Relevance + score.SenseOrder + score.Polysemy + score.Headword + score.Coverage
+
+	return score
+}
+
+// Priors - the most the priors can add to the relevance of a match. Matches no more relevant
+// than r never score more than r + Priors, so they need not be ranked to fill a page whose
+// matches all score more than that.
+func Priors(weights types.Ranking) float64 {
+	return weights.SenseOrder + weights.Polysemy + weights.Headword + weights.Coverage
+}
+
+// Coverage - the share of the terms of a text that the query searches for, from 0 to 1.
+// Queries that spell out a whole headword or gloss are after that very word or sense.
+func Coverage(terms []string, text string) float64 {
+	var tokens = Tokenize(text)
+	if len(tokens) == 0 || len(terms) == 0 {
+		return 0
+	}
+
+	var covered int
+
+	for _, token := range tokens {
+		for _, term := range terms {
+			if prefix, ok := strings.CutSuffix(term, "*"); token == term || ok && strings.HasPrefix(token, prefix) {
+				covered++
+				break
+			}
+		}
+	}
+
+	return float64(covered) / float64(len(tokens))
+}
@@ -0,0 +1,82 @@
+package ranking_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/ranking"
+)
+
+func Test_Tokens(t *testing.T) {
+	want := []ranking.Token{{Term: "cafe", Start: 0, End: 5}, {Term: "au", Start: 6, End: 8}, {Term: "lait", Start: 9, End: 13}}
+
+	if got := ranking.Tokens("Café-au-lait!"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokens(Café-au-lait!) = %v, want %v", got, want)
+	}
+}
+
+func Test_Coverage(t *testing.T) {
+	tests := []struct {
+		terms []string
+		text  string
+		want  float64
+	}{
+		{terms: []string{"a", "young", "dog"}, text: "a young dog", want: 1},
+		{terms: []string{"young", "dog"}, text: "a young dog", want: 2.0 / 3},
+		{terms: []string{"domestic*"}, text: "domestic dog", want: 0.5},
+		{terms: []string{"dog"}, text: "", want: 0},
+		{terms: nil, text: "a young dog", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := ranking.Coverage(tt.terms, tt.text); got != tt.want {
+				t.Errorf("Coverage(%v, %q) = %v, want %v", tt.terms, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Score(t *testing.T) {
+	weights := types.Ranking{SenseOrder: 2, Polysemy: 2, Headword: 4, Coverage: 4}
+
+	tests := []struct {
+		name  string
+		match ranking.Match
+		want  types.Score
+	}{
+		{
+			name:  "first member of a polysemous word",
+			match: ranking.Match{Relevance: 10, Position: 0, Senses: 4, Word: "puppy", Gloss: "a young dog"},
+			want:  types.Score{Total: 17.5, Relevance: 10, SenseOrder: 2, Polysemy: 1.5, Coverage: 4},
+		},
+		{
+			name:  "later member of a monosemous word",
+			match: ranking.Match{Relevance: 10, Position: 3, Senses: 1, Word: "young dog", Gloss: "a dog"},
+			want:  types.Score{Total: 18.5, Relevance: 10, SenseOrder: 0.5, Headword: 4, Coverage: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ranking.Score(weights, []string{"a", "young", "dog"}, tt.match); got != tt.want {
+				t.Errorf("Score(%+v) = %+v, want %+v", tt.match, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Priors(t *testing.T) {
+	weights := types.Ranking{SenseOrder: 2, Polysemy: 2, Headword: 4, Coverage: 4}
+
+	if got := ranking.Priors(weights); got != 12 {
+		t.Fatalf("Priors() = %v, want 12", got)
+	}
+
+	/* The most any match can score: first of its synset, of a word with countless senses, spelled out whole */
+	match := ranking.Match{Relevance: 10, Position: 0, Senses: 1 << 30, Word: "young dog", Gloss: "a young dog"}
+	if got := ranking.Score(weights, []string{"a", "young", "dog"}, match); got.Total > match.Relevance+ranking.Priors(weights) {
+		t.Errorf("Score() = %v, more than %v", got.Total, match.Relevance+ranking.Priors(weights))
+	}
+}
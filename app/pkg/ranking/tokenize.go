@@ -0,0 +1,58 @@
+package ranking
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Tokenize - splits text into lowercase terms without diacritics, on anything
+// other than letters and digits (i.e Café-au-lait → cafe, au, lait), as FTS5's
+// default tokenizer does.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(fold(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func fold(text string) string {
+	text = strings.ToLower(text)
+
+	for _, r := range text {
+		if r >= unicode.MaxASCII {
+			folded, _, _ := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), text)
+			return folded
+		}
+	}
+
+	return text
+}
+
+// Token - a term of a text, along with its bytes in the text.
+type Token struct {
+	Term       string
+	Start, End int
+}
+
+// Tokens - splits text into terms as Tokenize does, keeping where each was found.
+func Tokens(text string) []Token {
+	var tokens []Token
+	var start = -1
+
+	for i, r := range text + " " {
+		switch inside := unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r); {
+		case inside && start < 0:
+			start = i
+		case !inside && start >= 0:
+			if term := fold(text[start:i]); term != "" {
+				tokens = append(tokens, Token{Term: term, Start: start, End: i})
+			}
+			start = -1
+		}
+	}
+
+	return tokens
+}
@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+	"github.com/sirupsen/logrus"
+)
+
+// connector is satisfied by *sql.DB, whose connections expose the underlying pgx connection.
+type connector interface {
+	Conn(context.Context) (*sql.Conn, error)
+}
+
+// copySynsets - Streams a batch into temporary staging tables with COPY and merges it
+// into the dictionary with set-based upserts, all within one PostgreSQL transaction.
+func (repo *DictionaryRepository) copySynsets(ctx context.Context, synsets []types.NewSynsetInput, relations []types.Relation) error {
+	db, ok := repo._db.(connector)
+	if !ok {
+		return fmt.Errorf("%v: database does not expose its connections", helpers.GetCurrentFuncName())
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var members, examples [][]any
+
+	for _, item := range synsets {
+		for position, member := range item.Members {
+			members = append(members, []any{item.Id, member, item.PartOfSpeech, types.LexiconLanguage(item.Language), position, item.Explicit})
+		}
+
+		for position, example := range item.Examples {
+			examples = append(examples, []any{item.Id, position, example.Text, example.Source})
+		}
+
+		relations = append(relations, item.Relations...)
+	}
+
+	staging := []struct {
+		table   string
+		columns []string
+		rows    [][]any
+	}{
+		{
+			table:   "staging_synsets",
+			columns: []string{"id", "ili", "part_of_speech", "language", "explanation", "source", "checksum", "lexfile"},
+			rows: helpers.Map(synsets, func(_ int, s types.NewSynsetInput) []any {
+				return []any{s.Id, s.ILI, s.PartOfSpeech, types.LexiconLanguage(s.Language), s.Definition(), s.Source, s.Checksum(), s.Lexfile}
+			}),
+		},
+		{
+			table:   "staging_members",
+			columns: []string{"synset_id", "word", "part_of_speech", "language", "position", "explicit"},
+			rows:    members,
+		},
+		{
+			table:   "staging_examples",
+			columns: []string{"synset_id", "position", "text", "source"},
+			rows:    examples,
+		},
+		{
+			table:   "staging_relations",
+			columns: []string{"source_id", "target_id", "relation_type"},
+			rows: helpers.Map(relations, func(_ int, r types.Relation) []any {
+				return []any{r.Source, r.Target, string(r.Type)}
+			}),
+		},
+	}
+
+	create := []string{
+		`CREATE TEMPORARY TABLE staging_synsets (id TEXT, ili TEXT, part_of_speech TEXT, language TEXT, explanation TEXT, source TEXT, checksum TEXT, lexfile TEXT) ON COMMIT DROP`,
+		`CREATE TEMPORARY TABLE staging_members (synset_id TEXT, word TEXT, part_of_speech TEXT, language TEXT, position INTEGER, explicit BOOLEAN) ON COMMIT DROP`,
+		`CREATE TEMPORARY TABLE staging_examples (synset_id TEXT, position INTEGER, text TEXT, source TEXT) ON COMMIT DROP`,
+		`CREATE TEMPORARY TABLE staging_relations (source_id TEXT, target_id TEXT, relation_type TEXT) ON COMMIT DROP`,
+	}
+
+	merge := []string{
+		`
+		INSERT INTO explanations(text)
+			SELECT DISTINCT explanation FROM staging_synsets
+			ON CONFLICT (text) DO NOTHING
+		`,
+		`
+		INSERT INTO synsets(id, ili, part_of_speech, language, explanation_id, source, checksum, lexfile)
+			SELECT DISTINCT ON (s.id)
+				s.id, NULLIF(s.ili, ''), s.part_of_speech, s.language, e.id, NULLIF(s.source, ''), s.checksum, NULLIF(s.lexfile, '')
+			FROM
+				staging_synsets s
+				JOIN explanations e ON e.text = s.explanation
+			ON CONFLICT (id)
+			DO UPDATE SET ili = excluded.ili, part_of_speech = excluded.part_of_speech, language = excluded.language, explanation_id = excluded.explanation_id, source = excluded.source, checksum = excluded.checksum, lexfile = excluded.lexfile
+		`,
+		`
+		INSERT INTO words(text, part_of_speech, language)
+			SELECT DISTINCT word, part_of_speech, language FROM staging_members
+			ON CONFLICT (text, part_of_speech, language) DO NOTHING
+		`,
+		`DELETE FROM associations a USING staging_members m WHERE a.synset_id = m.synset_id AND a.explicit = m.explicit`,
+		`
+		INSERT INTO associations(word_id, synset_id, position, explicit)
+			SELECT DISTINCT ON (w.id, m.synset_id)
+				w.id, m.synset_id, m.position, m.explicit
+			FROM
+				staging_members m
+				JOIN words w ON w.text = m.word AND w.part_of_speech = m.part_of_speech AND w.language = m.language
+			ORDER BY
+				w.id, m.synset_id, m.position
+			ON CONFLICT (word_id, synset_id)
+			DO UPDATE SET position = excluded.position, explicit = excluded.explicit
+		`,
+		`DELETE FROM examples x USING staging_synsets s WHERE x.synset_id = s.id`,
+		`
+		INSERT INTO examples(synset_id, position, text, source)
+			SELECT synset_id, position, text, NULLIF(source, '') FROM staging_examples
+		`,
+		/* Documents include examples, so they are written last */
+		`
+		UPDATE associations a
+			SET document = ` + document + `
+			FROM words w, synsets s, explanations e
+			WHERE w.id = a.word_id AND s.id = a.synset_id AND e.id = s.explanation_id
+				AND a.synset_id IN (SELECT id FROM staging_synsets)
+		`,
+		`DELETE FROM relations r USING staging_synsets s WHERE r.source_id = s.id`,
+		`
+		INSERT INTO relations(source_id, target_id, relation_type)
+			SELECT DISTINCT source_id, target_id, relation_type FROM staging_relations
+			ON CONFLICT (source_id, target_id, relation_type) DO NOTHING
+		`,
+	}
+
+	return conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("%v: expected a pgx connection, got %T", helpers.GetCurrentFuncName(), driverConn)
+		}
+
+		return pgx.BeginFunc(ctx, c.Conn(), func(tx pgx.Tx) error {
+			for _, query := range create {
+				if _, err := tx.Exec(ctx, query); err != nil {
+					return err
+				}
+			}
+
+			for _, s := range staging {
+				if len(s.rows) == 0 {
+					continue
+				}
+
+				if _, err := tx.CopyFrom(ctx, pgx.Identifier{s.table}, s.columns, pgx.CopyFromRows(s.rows)); err != nil {
+					logrus.Errorln("failed to copy", len(s.rows), "rows into", s.table)
+					return err
+				}
+			}
+
+			for _, query := range merge {
+				if _, err := tx.Exec(ctx, query); err != nil {
+					logrus.Errorln("failed to merge staged batch", err)
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
@@ -2,140 +2,257 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/oleoneto/redic/app/domain/protocols"
 	"github.com/oleoneto/redic/app/domain/types"
 
 	"github.com/oleoneto/redic/app/pkg/helpers"
+	"github.com/oleoneto/redic/app/pkg/highlight"
+	"github.com/oleoneto/redic/app/pkg/morphy"
+	"github.com/oleoneto/redic/app/pkg/ranking"
+	dbsql "github.com/oleoneto/redic/app/pkg/repositories/sql"
+	"github.com/oleoneto/redic/app/pkg/search"
 	"github.com/sirupsen/logrus"
 )
 
 type DictionaryRepository struct {
-	_db protocols.SqlBackend
+	_db     protocols.SqlBackend
+	dialect dbsql.Dialect
 }
 
 // Explicit interface conformance check
 var _ protocols.DictionaryBackend = (*DictionaryRepository)(nil)
 
-func NewDictionaryRepository(database protocols.SqlBackend) *DictionaryRepository {
-	return &DictionaryRepository{database}
+func NewDictionaryRepository(database protocols.SqlBackend, adapter protocols.SQLAdapter) *DictionaryRepository {
+	return &DictionaryRepository{_db: database, dialect: dbsql.DialectOf(adapter)}
 }
 
 // NewWords - Adds words to the dictionary database.
+//
+// Words that share a definition are stored as members of the same synset.
 func (repo *DictionaryRepository) NewWords(ctx context.Context, words []types.NewWordInput) error {
-	t := repo._db
-
-	/* Creates a new word entry if one does not yet exist */
-	newWord := `
-	INSERT INTO words(text, part_of_speech)
-		VALUES($1, $2)
-		ON CONFLICT (text, part_of_speech)
-		DO UPDATE SET text = $1, part_of_speech = $2
-	RETURNING id
-	`
-
-	/* Creates a new explanation if one does not yet exist */
-	newExplanation := `
-	INSERT INTO explanations(text)
-		VALUES($1)
-		ON CONFLICT(text)
-		DO UPDATE SET text = $1
-	RETURNING id
-	`
-
-	newAssociation := `
-		INSERT INTO associations(word_id, explanation_id) VALUES($1, $2) ON CONFLICT(word_id, explanation_id) DO NOTHING
-	`
-
-	for _, item := range words {
-		var wordId, explanationId int64
-
-		if r := t.QueryRowContext(ctx, newWord, item.Word, item.PartOfSpeech); r != nil {
-			if err := r.Scan(&wordId); err != nil {
-				logrus.Errorln("failed to add word", item.Word, "part_of_speech", item.PartOfSpeech)
-				return err
-			}
+	return repo.NewSynsets(ctx, types.Synsets(words))
+}
+
+// NewSynsets - Adds synsets, their members, examples and relations to the dictionary database.
+//
+// The whole batch is written in one transaction and rolled back if any synset fails.
+// PostgreSQL batches are copied into staging tables and merged with set-based upserts;
+// other adapters reuse prepared statements for every row.
+func (repo *DictionaryRepository) NewSynsets(ctx context.Context, synsets []types.NewSynsetInput) error {
+	if len(synsets) == 0 {
+		return nil
+	}
+
+	if repo.dialect.Adapter() == protocols.PostgreSQLAdapter {
+		return repo.copySynsets(ctx, synsets, nil)
+	}
+
+	return repo.transaction(ctx, func(t *sql.Tx) error {
+		stmts, err := prepareStatements(ctx, t, repo.dialect)
+		if err != nil {
+			return err
 		}
+		defer stmts.Close()
 
-		if e := t.QueryRowContext(ctx, newExplanation, item.Definition); e != nil {
-			if err := e.Scan(&explanationId); err != nil {
-				logrus.Errorln("failed to add explanation", item.Definition)
+		for _, item := range synsets {
+			if err := stmts.writeSynset(ctx, item); err != nil {
 				return err
 			}
 		}
 
-		_, err := t.ExecContext(ctx, newAssociation, wordId, explanationId)
+		return nil
+	})
+}
+
+// NewRelations - Adds typed edges between synsets in a single transaction.
+func (repo *DictionaryRepository) NewRelations(ctx context.Context, relations []types.Relation) error {
+	if len(relations) == 0 {
+		return nil
+	}
+
+	if repo.dialect.Adapter() == protocols.PostgreSQLAdapter {
+		return repo.copySynsets(ctx, nil, relations)
+	}
+
+	return repo.transaction(ctx, func(t *sql.Tx) error {
+		stmts, err := prepareStatements(ctx, t, repo.dialect)
 		if err != nil {
-			logrus.Errorln("failed to associate id and explanation for", item.Word, wordId, explanationId)
 			return err
 		}
-	}
+		defer stmts.Close()
 
-	return nil
+		for _, item := range relations {
+			if err := stmts.writeRelation(ctx, item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
-// AddWordDefinitions - Add new definitions to an existing word
-func (repo *DictionaryRepository) AddWordDefinitions(ctx context.Context, data types.UpdateDefinitionInput) (types.Definitions, error) {
-	var res types.Definitions
+// indexed - Whether words are indexed for full-text search in the redic_ table.
+// PostgreSQL indexes them in the document column of their association instead.
+func (repo *DictionaryRepository) indexed() bool {
+	return repo.dialect.Adapter() != protocols.PostgreSQLAdapter
+}
 
+// document - The tsvector PostgreSQL searches a synset member (a) by, given its word (w)
+// and the synset's explanation (e). Fields are labeled A, B and C, so that ranks can weigh them apart.
+const document = `
+	setweight(to_tsvector('simple', w.text), 'A')
+	|| setweight(to_tsvector('simple', e.text), 'B')
+	|| setweight(to_tsvector('simple', COALESCE((SELECT string_agg(x.text, E'\n' ORDER BY x.position) FROM examples x WHERE x.synset_id = a.synset_id), '')), 'C')`
+
+// transaction - Runs f inside a transaction, rolling it back if f fails.
+func (repo *DictionaryRepository) transaction(ctx context.Context, f func(*sql.Tx) error) error {
 	t, terr := repo._db.BeginTx(ctx, nil)
 	if terr != nil {
-		return res, terr
+		return terr
 	}
 
-	values := `(?, ?, ?), `
-	args := []any{data.Word, data.PartOfSpeech, data.Definitions}
+	if err := f(t); err != nil {
+		t.Rollback()
+		return err
+	}
 
-	values = strings.TrimSpace(values)
-	values = strings.TrimRight(values, ",")
+	return t.Commit()
+}
 
-	query := fmt.Sprintf(`
-	INSERT INTO
-		dictionary (word, part_of_speech, explanation)
-	VALUES %s
-	RETURNING *`, values)
+// AddWordDefinitions - Adds a definition to an existing word, as a synset of its own.
+func (repo *DictionaryRepository) AddWordDefinitions(ctx context.Context, data types.UpdateDefinitionInput) (types.Definitions, error) {
+	synsets := types.Synsets([]types.NewWordInput{{
+		Word:         data.Word,
+		PartOfSpeech: string(data.PartOfSpeech),
+		Definition:   data.Definitions,
+	}})
 
-	r, err := t.QueryContext(ctx, query, args...)
-	if err != nil {
+	if err := repo.NewSynsets(ctx, synsets); err != nil {
 		logrus.Errorln(helpers.GetCurrentFuncName(), err)
-		t.Rollback()
-		return res, err
+		return types.Definitions{}, err
 	}
-	defer r.Close()
 
-	return res, t.Commit()
+	return types.Definitions{Id: synsets[0].Id}, nil
 }
 
 // GetWordExplanation - Looks for the given word in the database dictionary and returns its definition(s).
+//
+// Unless the search is verbatim, an inflected word is also looked up by its base forms
+// (i.e geese → goose), and each such definition reports the lemma it was found under.
+// Irregular forms are always resolved (saw → see), while regular ones are only resolved
+// for parts of speech the word itself is not defined as (running → run, but not the adjective).
 func (repo *DictionaryRepository) GetWordExplanation(ctx context.Context, data types.GetWordDefinitionsInput) (types.WordDefinitions, error) {
 	var res = types.WordDefinitions{Definitions: []types.Definition{}, Word: data.Word}
-	var args = []any{data.Word}
 
-	partOfSpeechFilter := func() string {
-		if data.PartOfSpeech == "" {
-			return `IS NOT NULL`
+	definitions, err := repo.wordExplanations(ctx, data, []morphy.Lemma{{Word: data.Word}})
+	if err != nil {
+		return res, err
+	}
+
+	if !data.Verbatim && types.IsEnglish(data.Language) {
+		var defined = map[types.PartOfSpeech]bool{}
+		var synsets = map[string]bool{}
+
+		for _, definition := range definitions {
+			defined[definition.PartOfSpeech] = true
+			synsets[definition.SynsetId] = true
 		}
 
-		args = append(args, data.PartOfSpeech)
-		return "= $2"
-	}()
+		lemmas := helpers.Filter(morphy.Lemmas(data.Word, data.PartOfSpeech), func(_ int, lemma morphy.Lemma) bool {
+			if lemma.PartOfSpeech == types.Adjective1 {
+				return lemma.Irregular || !(defined[types.Adjective1] || defined[types.Adjective2])
+			}
+			return lemma.Irregular || !defined[lemma.PartOfSpeech]
+		})
+
+		if len(lemmas) != 0 {
+			inflections, err := repo.wordExplanations(ctx, data, lemmas)
+			if err != nil {
+				return res, err
+			}
+
+			for _, definition := range inflections {
+				if !synsets[definition.SynsetId] {
+					definition.Lemma = definition.Word
+					definitions = append(definitions, definition)
+				}
+			}
+		}
+	}
+
+	for _, definition := range definitions {
+		res.Definitions = append(res.Definitions, definition.Definition)
+	}
+
+	return res, repo.attachExamples(ctx, res.Definitions)
+}
+
+// explanation - a definition along with the word it was found under.
+type explanation struct {
+	types.Definition
+	Word string
+}
+
+// wordExplanations - Returns the definitions of any of the given words.
+func (repo *DictionaryRepository) wordExplanations(ctx context.Context, data types.GetWordDefinitionsInput, words []morphy.Lemma) ([]explanation, error) {
+	var res = []explanation{}
+	var q = dbsql.NewQuery(repo.dialect)
+
+	matches := []string{}
+	for _, word := range words {
+		match := `d.word = ` + q.Bind(word.Word)
+
+		switch word.PartOfSpeech {
+		case "":
+		case types.Adjective1, types.Adjective2:
+			match += ` AND d.part_of_speech IN (` + q.BindAll(types.Adjective1, types.Adjective2) + `)`
+		default:
+			match += ` AND d.part_of_speech = ` + q.Bind(word.PartOfSpeech)
+		}
 
+		matches = append(matches, `(`+match+`)`)
+	}
+
+	var filters dbsql.Conditions
+
+	if data.PartOfSpeech != "" && data.PartOfSpeech != types.ALL {
+		filters.Add(`d.part_of_speech = ` + q.Bind(data.PartOfSpeech))
+	}
+
+	if data.Language != "" {
+		filters.Add(languageFilter("d.language", q.Bind(data.Language)))
+	}
+
+	/* Synsets without a gloss of their own borrow one through their interlingual index */
 	query := fmt.Sprintf(`
 	SELECT
-		d.id, d.word, d.part_of_speech, d.explanation, COALESCE(a.explicit, FALSE) explicit
+		d.id,
+		d.word,
+		d.part_of_speech,
+		COALESCE(NULLIF(d.explanation, ''), (
+			SELECT e.text FROM synsets s JOIN explanations e ON e.id = s.explanation_id
+			WHERE s.ili = d.ili AND e.text <> ''
+			LIMIT 1
+		), ''),
+		d.explicit,
+		d.synset_id,
+		COALESCE(d.ili, ''),
+		d.language,
+		COALESCE(d.lexfile, '')
 	FROM
 		dictionary d
-		JOIN associations a
-			ON a.explanation_id = d.explanation_id
-			AND a.word_id = d.id
 	WHERE
-		d.word = $1
-		AND d.part_of_speech %s
-	`, partOfSpeechFilter)
+		(%s) %s
+	ORDER BY
+		d.language, d.part_of_speech, d.synset_id
+	`, strings.Join(matches, " OR "), filters.And())
 
-	r, err := repo._db.QueryContext(ctx, query, args...)
+	r, err := repo._db.QueryContext(ctx, query, q.Args()...)
 	if err != nil {
 		return res, err
 	}
@@ -144,117 +261,651 @@ func (repo *DictionaryRepository) GetWordExplanation(ctx context.Context, data t
 	for r.Next() {
 		var id int
 		var explicit bool
-		var word, definition, partOfSpeech string
-		if err := r.Scan(&id, &word, &partOfSpeech, &definition, &explicit); err != nil {
+		var word, definition, partOfSpeech, synsetId, ili, language, lexfile string
+		if err := r.Scan(&id, &word, &partOfSpeech, &definition, &explicit, &synsetId, &ili, &language, &lexfile); err != nil {
 			return res, err
 		}
 
-		res.Definitions = append(res.Definitions, types.Definition{
-			PartOfSpeech: types.PartOfSpeech(partOfSpeech),
-			Definition:   definition,
-			Explicit:     explicit,
+		res = append(res, explanation{
+			Word: word,
+			Definition: types.Definition{
+				SynsetId:     synsetId,
+				ILI:          ili,
+				PartOfSpeech: types.PartOfSpeech(partOfSpeech),
+				Definition:   definition,
+				Explicit:     explicit,
+				Language:     language,
+				Lexfile:      lexfile,
+			},
 		})
 	}
 
-	return res, nil
+	return res, r.Err()
 }
 
-// SearchWords - Looks for all matching words for the provided word context.
+// attachExamples - Loads the example sentences of every definition's synset.
+func (repo *DictionaryRepository) attachExamples(ctx context.Context, definitions []types.Definition) error {
+	if len(definitions) == 0 {
+		return nil
+	}
+
+	examples, err := repo.examples(ctx, helpers.Map(definitions, func(_ int, d types.Definition) string { return d.SynsetId }))
+	if err != nil {
+		return err
+	}
+
+	for i := range definitions {
+		definitions[i].Examples = examples[definitions[i].SynsetId]
+	}
+
+	return nil
+}
+
+// examples - Loads the example sentences of the given synsets, in order, by synset id.
+func (repo *DictionaryRepository) examples(ctx context.Context, synsetIds []string) (map[string][]types.Example, error) {
+	var examples = map[string][]types.Example{}
+	var q = dbsql.NewQuery(repo.dialect)
+
+	query := fmt.Sprintf(`
+	SELECT
+		synset_id, text, COALESCE(source, '')
+	FROM
+		examples
+	WHERE
+		synset_id IN (%s)
+	ORDER BY
+		synset_id, position
+	`, q.BindAll(helpers.Map(synsetIds, func(_ int, id string) any { return id })...))
+
+	r, err := repo._db.QueryContext(ctx, query, q.Args()...)
+	if err != nil {
+		return examples, err
+	}
+	defer r.Close()
+
+	for r.Next() {
+		var synsetId string
+		var example types.Example
+		if err := r.Scan(&synsetId, &example.Text, &example.Source); err != nil {
+			return examples, err
+		}
+
+		examples[synsetId] = append(examples[synsetId], example)
+	}
+
+	return examples, r.Err()
+}
+
+// SearchWords - Looks for all matching words for the provided word context, best matches first.
+//
+// The most relevant matches by field-weighted full-text rank are ranked again in Go,
+// with the priors of the ranking package, and paged through there. Searches without
+// a description are listed by word and paged through in SQL.
+// Given a target language, matches are translated into the words of that language
+// that share their interlingual index (i.e a Portuguese description yields English words).
 func (repo *DictionaryRepository) SearchWords(ctx context.Context, data types.GetDescribedWordsInput) (types.WordMatches, error) {
 	var res = types.WordMatches{ProvidedDescriptions: data.Tokens, MatchingWords: []types.MatchingWord{}}
 
-	var args = []any{data.Tokens}
+	var weights = data.Weights()
+	var size = data.PageSize()
+	var listing = data.Tokens == ""
 
-	filters := func() string {
-		f := []string{}
+	var query search.Expr
 
-		if data.Cursor != "" {
-			f = append(f, fmt.Sprintf(`id > $%d`, len(args)+1))
-			args = append(args, data.Cursor)
+	if !listing {
+		var err error
+		if query, err = search.Parse(data.Tokens); err != nil {
+			return res, err
 		}
 
-		if data.PartOfSpeech != "" {
-			args = append(args, data.PartOfSpeech)
-			f = append(f, fmt.Sprintf(`part_of_speech = $%d`, len(args)+1))
+		if types.IsEnglish(data.Language) {
+			if query, res.Lemmas, err = repo.lemmatizeQuery(ctx, query); err != nil {
+				return res, err
+			}
 		}
+	}
 
-		if len(f) == 0 {
-			return ""
+	/*
+		Only matches no more relevant than the cursor's score can follow it. Those are ranked in windows
+		of the most relevant first, and the window widens until the page, and the match past it, score
+		more than any match left out could, or SQL has no more.
+	*/
+	for window := ranking.Candidates; ; window *= 2 {
+		matches, total, floor, err := repo.searchWords(ctx, data, query, weights, window)
+		if err != nil {
+			return res, err
 		}
 
-		return `WHERE ` + strings.Join(f, "AND")
-	}()
+		res.MatchingWords = matches
+
+		if listing {
+			res.Page(nil, size)
+			return res, nil
+		}
+
+		sort.Slice(res.MatchingWords, func(i, j int) bool {
+			return res.MatchingWords[i].Key().Compare(res.MatchingWords[j].Key()) < 0
+		})
+
+		var start int
+		if data.After != nil {
+			start = sort.Search(len(matches), func(i int) bool { return matches[i].Key().Compare(*data.After) > 0 })
+		}
+
+		settled := start+size < len(matches) && matches[start+size].Score.Total > floor+ranking.Priors(weights)
+
+		if settled || total <= window {
+			res.Page(data.After, size)
+			break
+		}
+	}
+
+	res.Ranking = &weights
 
-	query := func() string {
-		if data.Tokens != "" {
+	if err := repo.highlight(ctx, res.MatchingWords, query); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// searchWords - the matches of a search among the window of its most relevant candidates, scored,
+// how many candidates there are in all, and the relevance of the least relevant of the window.
+// Listings hold the page after the cursor, and one more row.
+func (repo *DictionaryRepository) searchWords(ctx context.Context, data types.GetDescribedWordsInput, query search.Expr, weights types.Ranking, window int) ([]types.MatchingWord, int, float64, error) {
+	var matches = []types.MatchingWord{}
+	var total int
+	var floor float64
+
+	var size = data.PageSize()
+	var listing = query == nil
+	var translate = data.TargetLanguage != "" && data.TargetLanguage != data.Language
+
+	var q = dbsql.NewQuery(repo.dialect)
+
+	/* The search terms are bound first, as they come first in the query */
+	var fullText dbsql.FullText
+	if !listing {
+		fullText = q.FullText(q.Bind(q.SearchTerms(query)), weights)
+	}
+
+	var filters dbsql.Conditions
+
+	if data.PartOfSpeech != "" {
+		filters.Add(`part_of_speech = ` + q.Bind(data.PartOfSpeech))
+	}
+
+	if data.Language != "" {
+		filters.Add(languageFilter("language", q.Bind(data.Language)))
+	}
+
+	if data.Category != "" {
+		filters.Add(`synset_id IN (SELECT id FROM synsets WHERE lexfile = ` + q.Bind(data.Category) + `)`)
+	}
+
+	/* Listings are paged through in SQL, in its order of words. Translated, only their translations are */
+	var limit string
+	switch {
+	case listing && !translate:
+		limit = fmt.Sprintf(`LIMIT %d`, size+1)
+
+		if data.After != nil {
+			filters.Add(after(q, "", *data.After))
+		}
+	case !listing && !translate && data.After != nil:
+		/* Priors add to relevance, so what follows the cursor scores no more than it, and is no more relevant (with slack for rounding) */
+		filters.Add(`rank >= ` + q.Bind(-data.After.Rank-1e-9))
+	}
+
+	statement := func() string {
+		if !listing {
 			return fmt.Sprintf(`
 			SELECT
-				word_id,
-				word,
-				w.part_of_speech,
-				definition,
-				highlight (redic_, 2, '<b>', '</b>') AS matched
-			FROM
-				redic_ ($1)
-				JOIN words w ON redic_.word_id = w.id
+				id, synset_id, word, part_of_speech, explanation, language, lexfile, rank, position,
+				(SELECT COUNT(*) FROM associations o WHERE o.word_id = m.id) AS senses,
+				COUNT(*) OVER () AS total,
+				rank AS floor
+			FROM (
+				SELECT
+					w.id,
+					s.id AS synset_id,
+					w.text AS word,
+					w.part_of_speech,
+					e.text AS explanation,
+					w.language,
+					COALESCE(s.lexfile, '') AS lexfile,
+					%s AS rank,
+					a.position
+				FROM
+					%s
+				WHERE
+					%s
+			) m
 			%s
 			ORDER BY
-				RANK
-			LIMIT 100
-			`, filters)
+				rank
+			LIMIT %d
+			`, fullText.Rank, fullText.From, fullText.Match, filters.Where(), window)
 		}
 
 		return fmt.Sprintf(`
 		SELECT
 			id,
+			synset_id,
 			word,
 			part_of_speech,
 			explanation,
-			"" AS highlight
+			language,
+			COALESCE(lexfile, '') AS lexfile,
+			0 AS rank,
+			position,
+			0 AS senses,
+			0 AS total,
+			0 AS floor
 		FROM
 			dictionary
 		%s
 		ORDER BY
-			word
-		LIMIT 100
-		`, filters)
+			word, id, synset_id
+		%s
+		`, filters.Where(), limit)
 	}()
 
-	r, err := repo._db.QueryContext(ctx, query, args...)
+	if translate {
+		var translations dbsql.Conditions
+		if limit = ""; listing {
+			limit = fmt.Sprintf(`LIMIT %d`, size+1)
+
+			if data.After != nil {
+				translations.Add(after(q, "t.", *data.After))
+			}
+		}
+
+		statement = fmt.Sprintf(`
+		WITH matches AS (%s)
+		SELECT
+			t.id,
+			t.synset_id,
+			t.word,
+			t.part_of_speech,
+			t.explanation,
+			t.language,
+			COALESCE(t.lexfile, '') AS lexfile,
+			MIN(m.rank) AS rank,
+			t.position,
+			(SELECT COUNT(*) FROM associations o WHERE o.word_id = t.id) AS senses,
+			MAX(m.total) AS total,
+			(SELECT MAX(rank) FROM matches) AS floor
+		FROM
+			matches m
+			JOIN synsets s ON s.id = m.synset_id
+			JOIN dictionary t ON t.ili = s.ili AND %s
+		%s
+		GROUP BY
+			t.id, t.synset_id, t.word, t.part_of_speech, t.explanation, t.language, t.lexfile, t.position
+		ORDER BY
+			MIN(m.rank), t.word, t.id, t.synset_id
+		%s
+		`, statement, languageFilter("t.language", q.Bind(data.TargetLanguage)), translations.Where(), limit)
+	}
+
+	r, err := repo._db.QueryContext(ctx, statement, q.Args()...)
 	if err != nil {
-		return res, err
+		return matches, total, floor, err
 	}
 	defer r.Close()
 
+	var terms = search.Terms(query)
+
 	for r.Next() {
-		var id int
-		var word, partOfSpeech, definition, highlight string
+		var id, position, senses int
+		var rank float64
+		var synsetId, word, partOfSpeech, definition, language, lexfile string
 
-		if err := r.Scan(&id, &word, &partOfSpeech, &definition, &highlight); err != nil {
-			return res, err
+		/* Matches come by rank, so the last one holds the floor of the window. Translations all hold it */
+		if err := r.Scan(&id, &synsetId, &word, &partOfSpeech, &definition, &language, &lexfile, &rank, &position, &senses, &total, &floor); err != nil {
+			return matches, total, floor, err
 		}
 
-		res.MatchingWords = append(res.MatchingWords, types.MatchingWord{
+		match := types.MatchingWord{
 			Id:           id,
+			SynsetId:     synsetId,
 			Word:         word,
 			PartOfSpeech: types.PartOfSpeech(partOfSpeech),
 			Definition:   definition,
-		})
-	}
+			Language:     language,
+			Lexfile:      lexfile,
+		}
+
+		if !listing {
+			match.Score = helpers.PointerTo(ranking.Score(weights, terms, ranking.Match{
+				Relevance: -rank,
+				Position:  position,
+				Senses:    senses,
+				Word:      word,
+				Gloss:     definition,
+			}))
+		}
 
-	if len(res.MatchingWords) > 0 {
-		res.Cursor = fmt.Sprint(res.MatchingWords[len(res.MatchingWords)-1].Id)
+		matches = append(matches, match)
 	}
 
-	return res, nil
+	return matches, total, -floor, r.Err()
 }
 
-func (repo *DictionaryRepository) IndexWords(ctx context.Context) error {
-	query := `DELETE FROM redic_; INSERT INTO redic_ (word_id, word, definition) SELECT id, word, explanation FROM dictionary`
+// after - the condition that rows of a listing come after the cursor, in the order of words.
+// Columns are qualified with the given prefix (i.e t.).
+func after(q *dbsql.Query, prefix string, cursor types.Cursor) string {
+	return fmt.Sprintf(`(%[1]sword, %[1]sid, %[1]ssynset_id) > (%s)`, prefix, q.BindAll(cursor.Word, cursor.Id, cursor.SynsetId))
+}
 
-	if _, err := repo._db.ExecContext(ctx, query); err != nil {
+// highlight - Highlights where the query matched every match, looking into the examples
+// of those whose definition it did not match.
+func (repo *DictionaryRepository) highlight(ctx context.Context, matches []types.MatchingWord, query search.Expr) error {
+	var phrases = search.Phrases(query)
+	var unmatched []string
+
+	for i := range matches {
+		if highlight.Match(&matches[i], phrases, nil); matches[i].Snippet == nil {
+			unmatched = append(unmatched, matches[i].SynsetId)
+		}
+	}
+
+	if len(unmatched) == 0 {
+		return nil
+	}
+
+	examples, err := repo.examples(ctx, unmatched)
+	if err != nil {
 		return err
 	}
 
+	for i, m := range matches {
+		if m.Snippet == nil {
+			highlight.Match(&matches[i], phrases, helpers.Map(examples[m.SynsetId], func(_ int, e types.Example) string { return e.Text }))
+		}
+	}
+
 	return nil
 }
+
+// languageFilter - matches a language column against the tag bound to the placeholder.
+//
+// Tags match their more and less specific forms alike, so pt finds pt-BR lexicons and pt-PT finds pt ones.
+func languageFilter(column string, placeholder string) string {
+	return fmt.Sprintf(`(%[1]s = %[2]s OR %[1]s LIKE %[2]s || '-%%' OR %[2]s LIKE %[1]s || '-%%')`, column, placeholder)
+}
+
+// GetRelatedWords - Looks for words whose synsets are linked to any synset of the given word.
+//
+// Relations are followed in both directions, so hypernyms and hyponyms are returned alike.
+func (repo *DictionaryRepository) GetRelatedWords(ctx context.Context, data types.GetRelatedWordsInput) (types.RelatedWords, error) {
+	var res = types.RelatedWords{Word: data.Word, Relations: []types.RelatedWord{}}
+	var q = dbsql.NewQuery(repo.dialect)
+
+	var word = q.Bind(data.Word)
+	var filters dbsql.Conditions
+
+	if data.PartOfSpeech != "" && data.PartOfSpeech != types.ALL {
+		filters.Add(`s.part_of_speech = ` + q.Bind(data.PartOfSpeech))
+	}
+
+	if data.RelationType != "" {
+		filters.Add(`r.relation_type = ` + q.Bind(data.RelationType))
+	}
+
+	/* Both halves share the same arguments */
+	query := fmt.Sprintf(`
+	SELECT
+		r.relation_type, FALSE AS inverse, t.word, t.part_of_speech, t.explanation
+	FROM
+		dictionary s
+		JOIN relations r ON r.source_id = s.synset_id
+		JOIN dictionary t ON t.synset_id = r.target_id
+	WHERE
+		s.word = %[1]s %[2]s
+	UNION ALL
+	SELECT
+		r.relation_type, TRUE AS inverse, t.word, t.part_of_speech, t.explanation
+	FROM
+		dictionary s
+		JOIN relations r ON r.target_id = s.synset_id
+		JOIN dictionary t ON t.synset_id = r.source_id
+	WHERE
+		s.word = %[1]s %[2]s
+	ORDER BY
+		1, 2, 3
+	`, word, filters.And())
+
+	r, err := repo._db.QueryContext(ctx, query, q.Args()...)
+	if err != nil {
+		return res, err
+	}
+	defer r.Close()
+
+	for r.Next() {
+		var inverse bool
+		var relation, word, partOfSpeech, definition string
+		if err := r.Scan(&relation, &inverse, &word, &partOfSpeech, &definition); err != nil {
+			return res, err
+		}
+
+		res.Relations = append(res.Relations, types.RelatedWord{
+			Word:         word,
+			PartOfSpeech: types.PartOfSpeech(partOfSpeech),
+			Definition:   definition,
+			Relation:     types.RelationType(relation),
+			Inverse:      inverse,
+		})
+	}
+
+	return res, r.Err()
+}
+
+// ExportSynsets - Reads back every synset of the dictionary with its members, examples and relations.
+//
+// Members that are explicit for a synset are emitted as a synset of their own,
+// the way they are grouped when imported.
+func (repo *DictionaryRepository) ExportSynsets(ctx context.Context, emit func(types.NewSynsetInput) error) error {
+	examples, err := repo.allExamples(ctx)
+	if err != nil {
+		return err
+	}
+
+	relations, err := repo.allRelations(ctx)
+	if err != nil {
+		return err
+	}
+
+	r, err := repo._db.QueryContext(ctx, `
+	SELECT
+		d.synset_id, d.word, d.part_of_speech, d.explanation, d.explicit, d.language, COALESCE(d.ili, ''), COALESCE(d.lexfile, '')
+	FROM
+		dictionary d
+	ORDER BY
+		d.synset_id, d.explicit, d.position, d.word
+	`)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var current *types.NewSynsetInput
+
+	flush := func() error {
+		if current == nil {
+			return nil
+		}
+
+		current.Examples = examples[current.Id]
+		current.Relations = relations[current.Id]
+		return emit(*current)
+	}
+
+	for r.Next() {
+		var explicit bool
+		var synsetId, word, partOfSpeech, definition, language, ili, lexfile string
+		if err := r.Scan(&synsetId, &word, &partOfSpeech, &definition, &explicit, &language, &ili, &lexfile); err != nil {
+			return err
+		}
+
+		if current == nil || current.Id != synsetId || current.Explicit != explicit {
+			if err := flush(); err != nil {
+				return err
+			}
+
+			current = &types.NewSynsetInput{
+				Id:           synsetId,
+				PartOfSpeech: partOfSpeech,
+				Definitions:  []string{definition},
+				Explicit:     explicit,
+				Language:     language,
+				ILI:          ili,
+				Lexfile:      lexfile,
+			}
+		}
+
+		current.Members = append(current.Members, word)
+	}
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+func (repo *DictionaryRepository) allExamples(ctx context.Context) (map[string][]types.Example, error) {
+	var examples = map[string][]types.Example{}
+
+	r, err := repo._db.QueryContext(ctx, `SELECT synset_id, text, COALESCE(source, '') FROM examples ORDER BY synset_id, position`)
+	if err != nil {
+		return examples, err
+	}
+	defer r.Close()
+
+	for r.Next() {
+		var synsetId string
+		var example types.Example
+		if err := r.Scan(&synsetId, &example.Text, &example.Source); err != nil {
+			return examples, err
+		}
+
+		examples[synsetId] = append(examples[synsetId], example)
+	}
+
+	return examples, r.Err()
+}
+
+func (repo *DictionaryRepository) allRelations(ctx context.Context) (map[string][]types.Relation, error) {
+	var relations = map[string][]types.Relation{}
+
+	r, err := repo._db.QueryContext(ctx, `SELECT source_id, target_id, relation_type FROM relations ORDER BY source_id, relation_type, target_id`)
+	if err != nil {
+		return relations, err
+	}
+	defer r.Close()
+
+	for r.Next() {
+		var relation types.Relation
+		if err := r.Scan(&relation.Source, &relation.Target, &relation.Type); err != nil {
+			return relations, err
+		}
+
+		relations[relation.Source] = append(relations[relation.Source], relation)
+	}
+
+	return relations, r.Err()
+}
+
+// IndexWords - Rebuilds or optimizes the full-text index.
+//
+// Writes keep the index up to date, so rebuilding is only needed to repair an index that
+// went out of step, i.e after editing the database by hand. Optimizing merges the index
+// into as few parts as it can, which speeds up searches after large imports.
+func (repo *DictionaryRepository) IndexWords(ctx context.Context, maintenance types.IndexMaintenance) error {
+	var query string
+
+	switch {
+	case maintenance != types.RebuildIndex && maintenance != types.OptimizeIndex:
+		return fmt.Errorf("unknown index maintenance %q", maintenance)
+	case repo.indexed():
+		query = fmt.Sprintf(`INSERT INTO redic_ (redic_) VALUES ('%s')`, maintenance)
+	case maintenance == types.RebuildIndex:
+		query = `
+		UPDATE associations a
+			SET document = ` + document + `
+			FROM words w, synsets s, explanations e
+			WHERE w.id = a.word_id AND s.id = a.synset_id AND e.id = s.explanation_id
+		`
+	default:
+		/* Moves entries pending in the GIN index into its main structure */
+		query = `SELECT gin_clean_pending_list('associations_document'::regclass)`
+	}
+
+	_, err := repo._db.ExecContext(ctx, query)
+	return err
+}
+
+// SuspendIndexing - Stops writes from keeping the full-text index up to date, so that large
+// imports write every row once instead of reindexing its synset along with it. The function
+// returned brings index maintenance back and rebuilds the index from the dictionary.
+//
+// SQLite keeps its index up to date through triggers, which are dropped until then; should
+// the process die before, migrating from scratch brings them back. PostgreSQL writes the
+// document of an association along with it, so there is nothing to suspend.
+func (repo *DictionaryRepository) SuspendIndexing(ctx context.Context) (func(context.Context) error, error) {
+	if !repo.indexed() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var triggers []string
+
+	err := repo.transaction(ctx, func(t *sql.Tx) error {
+		rows, err := t.QueryContext(ctx, `SELECT name, sql FROM sqlite_master WHERE type = 'trigger' AND instr(sql, 'redic_') > 0 ORDER BY name`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var names []string
+		for rows.Next() {
+			var name, trigger string
+			if err := rows.Scan(&name, &trigger); err != nil {
+				return err
+			}
+
+			names, triggers = append(names, name), append(triggers, trigger)
+		}
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			if _, err := t.ExecContext(ctx, fmt.Sprintf(`DROP TRIGGER %q`, name)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resume := func(ctx context.Context) error {
+		return repo.transaction(ctx, func(t *sql.Tx) error {
+			for _, trigger := range triggers {
+				if _, err := t.ExecContext(ctx, trigger); err != nil {
+					return err
+				}
+			}
+
+			_, err := t.ExecContext(ctx, fmt.Sprintf(`INSERT INTO redic_ (redic_) VALUES ('%s')`, types.RebuildIndex))
+			return err
+		})
+	}
+
+	return resume, nil
+}
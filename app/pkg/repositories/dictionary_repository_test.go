@@ -0,0 +1,529 @@
+package repositories_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+	"github.com/oleoneto/redic/app/pkg/migrations"
+	"github.com/oleoneto/redic/app/pkg/repositories"
+	dbsql "github.com/oleoneto/redic/app/pkg/repositories/sql"
+)
+
+// Tests run against SQLite, through the driver that needs no CGO, and against the PostgreSQL
+// database of REDIC_TEST_DATABASE_URL when set. That database is wiped before and after each test.
+const postgresURL = "REDIC_TEST_DATABASE_URL"
+
+var synsets = []types.NewSynsetInput{
+	{
+		Id:           "02084071-n",
+		PartOfSpeech: "n",
+		Definitions:  []string{"a member of the genus Canis that has been domesticated by man since prehistoric times"},
+		Examples:     []types.Example{{Text: "the dog barked all night"}},
+		Members:      []string{"dog", "domestic dog"},
+		Relations:    []types.Relation{{Source: "02084071-n", Target: "02083346-n", Type: types.Hypernym}},
+		Source:       "noun.animal.yaml",
+		Lexfile:      "noun.animal",
+	},
+	{
+		Id:           "02083346-n",
+		PartOfSpeech: "n",
+		Definitions:  []string{"any of various fissiped mammals with nonretractile claws"},
+		Members:      []string{"canine"},
+		Source:       "noun.animal.yaml",
+		Lexfile:      "noun.animal",
+	},
+	{
+		Id:           "02114100-n",
+		PartOfSpeech: "n",
+		Definitions:  []string{"any of various wild canines of the genus Canis, hunting in packs"},
+		Members:      []string{"wolf"},
+		Relations:    []types.Relation{{Source: "02114100-n", Target: "02083346-n", Type: types.Hypernym}},
+		Source:       "noun.animal.yaml",
+		Lexfile:      "noun.animal",
+	},
+	{
+		Id:           "02077948-n",
+		PartOfSpeech: "n",
+		Definitions:  []string{"any of several large marine mammals; the sea lion and the walrus"},
+		Members:      []string{"seal"},
+		Source:       "noun.animal.yaml",
+		Lexfile:      "noun.animal",
+	},
+	{
+		Id:           "00014742-v",
+		PartOfSpeech: "v",
+		Definitions:  []string{"make a loud noise like a dog, as a dog does"},
+		Members:      []string{"bark"},
+		Source:       "verb.body.yaml",
+		Lexfile:      "verb.body",
+	},
+}
+
+type backend struct {
+	adapter    protocols.SQLAdapter
+	repository *repositories.DictionaryRepository
+}
+
+// backends - empty dictionaries, migrated up, of every adapter available.
+func backends(t *testing.T) []backend {
+	t.Helper()
+
+	var options = []protocols.DBConnectOptions{
+		{Adapter: protocols.PureSQLiteAdapter, Filename: filepath.Join(t.TempDir(), "redic.db")},
+	}
+
+	if dsn := os.Getenv(postgresURL); dsn != "" {
+		options = append(options, protocols.DBConnectOptions{Adapter: protocols.PostgreSQLAdapter, DSN: dsn})
+	}
+
+	var res []backend
+
+	for _, option := range options {
+		ctx := context.Background()
+
+		db, err := dbsql.ConnectDatabase(option)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		migrator, err := migrations.NewMigrator(db, option.Adapter)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		/* Whatever an earlier run left behind is reverted first */
+		if err := migrator.Down(ctx, math.MaxInt); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := migrator.Up(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := migrator.Down(ctx, math.MaxInt); err != nil {
+				t.Error(err)
+			}
+			if db, ok := db.(io.Closer); ok {
+				db.Close()
+			}
+		})
+
+		res = append(res, backend{adapter: option.Adapter, repository: repositories.NewDictionaryRepository(db, option.Adapter)})
+	}
+
+	return res
+}
+
+// seeded - the dictionaries of every adapter, with the synsets of the fixture.
+func seeded(t *testing.T) []backend {
+	t.Helper()
+
+	res := backends(t)
+
+	for _, b := range res {
+		if err := b.repository.NewSynsets(context.Background(), synsets); err != nil {
+			t.Fatalf("%s: NewSynsets() error = %v", b.adapter, err)
+		}
+	}
+
+	return res
+}
+
+func words(matches []types.MatchingWord) []string {
+	return helpers.Map(matches, func(_ int, m types.MatchingWord) string { return m.Word })
+}
+
+func Test_DictionaryRepository_NewSynsets(t *testing.T) {
+	for _, b := range seeded(t) {
+		t.Run(string(b.adapter), func(t *testing.T) {
+			ctx := context.Background()
+
+			/* Writing a synset again replaces it, rather than adding to it */
+			if err := b.repository.NewSynsets(ctx, synsets[:1]); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []types.NewSynsetInput
+			err := b.repository.ExportSynsets(ctx, func(s types.NewSynsetInput) error {
+				got = append(got, s)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := slices.Clone(synsets)
+			for i := range want {
+				want[i].Language = types.DefaultLanguage
+				want[i].Source = ""
+			}
+			slices.SortFunc(want, func(a, b types.NewSynsetInput) int { return strings.Compare(a.Id, b.Id) })
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ExportSynsets() = %+v, want %+v", got, want)
+			}
+
+			checksums, err := b.repository.GetSourceChecksums(ctx, "noun.animal.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := len(checksums); got != 4 || checksums["02084071-n"] != synsets[0].Checksum() {
+				t.Errorf("GetSourceChecksums() = %v, want 4 synsets with their checksums", checksums)
+			}
+		})
+	}
+}
+
+func Test_DictionaryRepository_GetWordExplanation(t *testing.T) {
+	for _, b := range seeded(t) {
+		t.Run(string(b.adapter), func(t *testing.T) {
+			tests := []struct {
+				input types.GetWordDefinitionsInput
+				want  []string // synset and lemma of every definition
+			}{
+				{input: types.GetWordDefinitionsInput{Word: "dog"}, want: []string{"02084071-n "}},
+				{input: types.GetWordDefinitionsInput{Word: "dogs"}, want: []string{"02084071-n dog"}},
+				{input: types.GetWordDefinitionsInput{Word: "wolves"}, want: []string{"02114100-n wolf"}},
+				{input: types.GetWordDefinitionsInput{Word: "barked", PartOfSpeech: types.Verb}, want: []string{"00014742-v bark"}},
+				{input: types.GetWordDefinitionsInput{Word: "dogs", Verbatim: true}, want: []string{}},
+				{input: types.GetWordDefinitionsInput{Word: "dog", PartOfSpeech: types.Verb}, want: []string{}},
+			}
+
+			for _, tt := range tests {
+				res, err := b.repository.GetWordExplanation(context.Background(), tt.input)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got := helpers.Map(res.Definitions, func(_ int, d types.Definition) string { return d.SynsetId + " " + d.Lemma })
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("GetWordExplanation(%+v) = %v, want %v", tt.input, got, tt.want)
+				}
+			}
+
+			res, err := b.repository.GetWordExplanation(context.Background(), types.GetWordDefinitionsInput{Word: "dogs"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := types.Definition{
+				SynsetId:     "02084071-n",
+				PartOfSpeech: types.Noun,
+				Definition:   "a member of the genus Canis that has been domesticated by man since prehistoric times",
+				Examples:     []types.Example{{Text: "the dog barked all night"}},
+				Language:     types.DefaultLanguage,
+				Lemma:        "dog",
+				Lexfile:      "noun.animal",
+			}
+
+			if !reflect.DeepEqual(res.Definitions, []types.Definition{want}) {
+				t.Errorf("GetWordExplanation(dogs) = %+v, want %+v", res.Definitions, want)
+			}
+		})
+	}
+}
+
+func Test_DictionaryRepository_SearchWords(t *testing.T) {
+	for _, b := range seeded(t) {
+		t.Run(string(b.adapter), func(t *testing.T) {
+			tests := []struct {
+				name   string
+				input  types.GetDescribedWordsInput
+				want   []string // in any order, as engines rank apart
+				lemmas map[string][]string
+			}{
+				{name: "every term", input: types.GetDescribedWordsInput{Tokens: "genus Canis"}, want: []string{"dog", "domestic dog", "wolf"}},
+				{name: "any term", input: types.GetDescribedWordsInput{Tokens: "walrus OR domesticated"}, want: []string{"dog", "domestic dog", "seal"}},
+				{name: "excluded term", input: types.GetDescribedWordsInput{Tokens: "Canis -wild"}, want: []string{"dog", "domestic dog"}},
+				{name: "excluded group", input: types.GetDescribedWordsInput{Tokens: "Canis -(wild hunting)"}, want: []string{"dog", "domestic dog"}},
+				{name: "excluded group with exclusions", input: types.GetDescribedWordsInput{Tokens: "Canis -(wild -hunting)"}, want: []string{"dog", "domestic dog", "wolf"}},
+				{name: "phrase", input: types.GetDescribedWordsInput{Tokens: `"sea lion"`}, want: []string{"seal"}},
+				{name: "prefix", input: types.GetDescribedWordsInput{Tokens: "domestic*"}, want: []string{"dog", "domestic dog"}},
+				{name: "inflection", input: types.GetDescribedWordsInput{Tokens: "hunting wolves"}, want: []string{"wolf"}, lemmas: map[string][]string{"wolves": {"wolf"}}},
+				{name: "part of speech", input: types.GetDescribedWordsInput{Tokens: "dog", PartOfSpeech: types.Verb}, want: []string{"bark"}},
+				{name: "category", input: types.GetDescribedWordsInput{Tokens: "dog", Category: "noun.animal"}, want: []string{"dog", "domestic dog"}},
+				{name: "language", input: types.GetDescribedWordsInput{Tokens: "dog", Language: "pt"}, want: []string{}},
+				{name: "no description", input: types.GetDescribedWordsInput{Category: "noun.animal"}, want: []string{"canine", "dog", "domestic dog", "seal", "wolf"}},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					res, err := b.repository.SearchWords(context.Background(), tt.input)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					got := words(res.MatchingWords)
+					slices.Sort(got)
+
+					if !reflect.DeepEqual(got, tt.want) {
+						t.Errorf("SearchWords(%q) = %v, want %v", tt.input.Tokens, got, tt.want)
+					}
+
+					if !reflect.DeepEqual(res.Lemmas, tt.lemmas) {
+						t.Errorf("SearchWords(%q) lemmas = %v, want %v", tt.input.Tokens, res.Lemmas, tt.lemmas)
+					}
+				})
+			}
+		})
+	}
+}
+
+func Test_DictionaryRepository_SearchWords_Pages(t *testing.T) {
+	/* More matches than are ranked at first, with priors that favor some of the least relevant */
+	var many []types.NewSynsetInput
+	for i := range 1500 {
+		var members = []string{fmt.Sprintf("thing %d", i)}
+		if i%7 == 0 {
+			members = []string{"thing", members[0]}
+		}
+
+		many = append(many, types.NewSynsetInput{
+			Id:           fmt.Sprintf("%08d-n", i),
+			PartOfSpeech: "n",
+			Definitions:  []string{"a thing" + strings.Repeat(" of some kind", i%40)},
+			Members:      members,
+		})
+	}
+
+	for _, b := range backends(t) {
+		t.Run(string(b.adapter), func(t *testing.T) {
+			ctx := context.Background()
+
+			if err := b.repository.NewSynsets(ctx, append(slices.Clone(synsets), many...)); err != nil {
+				t.Fatal(err)
+			}
+
+			tests := []struct {
+				name  string
+				input types.GetDescribedWordsInput
+				count int
+			}{
+				{name: "ranked", input: types.GetDescribedWordsInput{Tokens: "thing", Limit: 100}, count: 1500 + 1500/7 + 1},
+				{name: "ranked and filtered", input: types.GetDescribedWordsInput{Tokens: "dog", Category: "noun.animal", Limit: 1}, count: 2},
+				{name: "listed", input: types.GetDescribedWordsInput{Category: "noun.animal", Limit: 2}, count: 5},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					/* Pages add up to every match, neither skipping nor repeating any, best first */
+					var got []types.MatchingWord
+					var seen = map[types.Cursor]bool{}
+
+					for input := tt.input; ; {
+						page, err := b.repository.SearchWords(ctx, input)
+						if err != nil {
+							t.Fatal(err)
+						}
+
+						for _, m := range page.MatchingWords {
+							if seen[m.Key()] {
+								t.Fatalf("SearchWords(%q) repeats %s (%s)", input.Tokens, m.Word, m.SynsetId)
+							}
+							seen[m.Key()] = true
+						}
+
+						got = append(got, page.MatchingWords...)
+						if !page.HasMore {
+							break
+						}
+
+						if page.Next == nil || len(page.MatchingWords) != tt.input.Limit {
+							t.Fatalf("SearchWords(%q) = a page of %d matches, and a cursor %v", input.Tokens, len(page.MatchingWords), page.Next)
+						}
+
+						input.After = page.Next
+					}
+
+					if len(got) != tt.count {
+						t.Errorf("SearchWords(%q) in pages = %d matches, want %d", tt.input.Tokens, len(got), tt.count)
+					}
+
+					if !slices.IsSortedFunc(got, func(a, b types.MatchingWord) int { return a.Key().Compare(b.Key()) }) {
+						t.Errorf("SearchWords(%q) in pages = %v, out of order", tt.input.Tokens, words(got))
+					}
+				})
+			}
+		})
+	}
+}
+
+func Test_DictionaryRepository_GetRelatedWords(t *testing.T) {
+	for _, b := range seeded(t) {
+		t.Run(string(b.adapter), func(t *testing.T) {
+			tests := []struct {
+				input types.GetRelatedWordsInput
+				want  []types.RelatedWord
+			}{
+				{
+					input: types.GetRelatedWordsInput{Word: "canine"},
+					want: []types.RelatedWord{
+						{Word: "dog", PartOfSpeech: types.Noun, Definition: synsets[0].Definition(), Relation: types.Hypernym, Inverse: true},
+						{Word: "domestic dog", PartOfSpeech: types.Noun, Definition: synsets[0].Definition(), Relation: types.Hypernym, Inverse: true},
+						{Word: "wolf", PartOfSpeech: types.Noun, Definition: synsets[2].Definition(), Relation: types.Hypernym, Inverse: true},
+					},
+				},
+				{
+					input: types.GetRelatedWordsInput{Word: "wolf"},
+					want:  []types.RelatedWord{{Word: "canine", PartOfSpeech: types.Noun, Definition: synsets[1].Definition(), Relation: types.Hypernym}},
+				},
+				{input: types.GetRelatedWordsInput{Word: "wolf", RelationType: types.Similar}, want: []types.RelatedWord{}},
+				{input: types.GetRelatedWordsInput{Word: "canine", PartOfSpeech: types.Verb}, want: []types.RelatedWord{}},
+				{input: types.GetRelatedWordsInput{Word: "bark"}, want: []types.RelatedWord{}},
+			}
+
+			for _, tt := range tests {
+				res, err := b.repository.GetRelatedWords(context.Background(), tt.input)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !reflect.DeepEqual(res.Relations, tt.want) {
+					t.Errorf("GetRelatedWords(%+v) = %+v, want %+v", tt.input, res.Relations, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_DictionaryRepository_RecordSource(t *testing.T) {
+	for _, b := range seeded(t) {
+		t.Run(string(b.adapter), func(t *testing.T) {
+			ctx := context.Background()
+
+			source := types.Source{Name: "noun.animal.yaml", Hash: "a", Synsets: 4}
+			if err := b.repository.RecordSource(ctx, types.SourceUpdate{Source: source}); err != nil {
+				t.Fatal(err)
+			}
+
+			/* The verb belongs to another file, so it is not the file's to remove */
+			source.Hash, source.Synsets = "b", 3
+			if err := b.repository.RecordSource(ctx, types.SourceUpdate{Source: source, Stale: []string{"02114100-n", "00014742-v"}}); err != nil {
+				t.Fatal(err)
+			}
+
+			recorded, err := b.repository.GetSource(ctx, source.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := recorded; got.Hash != "b" || got.Synsets != 3 || got.ImportedAt.IsZero() {
+				t.Errorf("GetSource() = %+v, want hash b and 3 synsets", got)
+			}
+
+			for word, want := range map[string]int{"wolf": 0, "bark": 1, "canis": 2} {
+				res, err := b.repository.SearchWords(ctx, types.GetDescribedWordsInput{Tokens: word})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got := len(res.MatchingWords); got != want {
+					t.Errorf("SearchWords(%s) = %d matches after %s went stale, want %d", word, got, source.Name, want)
+				}
+			}
+
+			res, err := b.repository.GetWordExplanation(ctx, types.GetWordDefinitionsInput{Word: "wolf", Verbatim: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(res.Definitions) != 0 {
+				t.Errorf("GetWordExplanation(wolf) = %+v, want no definitions", res.Definitions)
+			}
+
+			related, err := b.repository.GetRelatedWords(ctx, types.GetRelatedWordsInput{Word: "canine"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := helpers.Map(related.Relations, func(_ int, r types.RelatedWord) string { return r.Word })
+			if want := []string{"dog", "domestic dog"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("GetRelatedWords(canine) = %v after wolf went stale, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_DictionaryRepository_SuspendIndexing(t *testing.T) {
+	for _, b := range backends(t) {
+		t.Run(string(b.adapter), func(t *testing.T) {
+			ctx := context.Background()
+
+			resume, err := b.repository.SuspendIndexing(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := b.repository.NewSynsets(ctx, synsets); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := resume(ctx); err != nil {
+				t.Fatalf("resume() error = %v", err)
+			}
+
+			/* Writes after resuming are indexed as they happen again */
+			err = b.repository.NewSynsets(ctx, []types.NewSynsetInput{{
+				Id:           "02115096-n",
+				PartOfSpeech: "n",
+				Definitions:  []string{"Old World nocturnal canine mammal closely related to the hyena"},
+				Members:      []string{"hyena dog"},
+				Source:       "noun.animal.yaml",
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for tokens, want := range map[string]int{"canis": 3, "hyena": 1} {
+				res, err := b.repository.SearchWords(ctx, types.GetDescribedWordsInput{Tokens: tokens})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got := len(res.MatchingWords); got != want {
+					t.Errorf("SearchWords(%s) = %d matches once indexing resumed, want %d", tokens, got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_DictionaryRepository_IndexWords(t *testing.T) {
+	for _, b := range seeded(t) {
+		t.Run(string(b.adapter), func(t *testing.T) {
+			ctx := context.Background()
+
+			for _, maintenance := range []types.IndexMaintenance{types.RebuildIndex, types.OptimizeIndex} {
+				if err := b.repository.IndexWords(ctx, maintenance); err != nil {
+					t.Fatalf("IndexWords(%s) error = %v", maintenance, err)
+				}
+
+				res, err := b.repository.SearchWords(ctx, types.GetDescribedWordsInput{Tokens: "canis"})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got := len(res.MatchingWords); got != 3 {
+					t.Errorf("SearchWords(canis) = %d matches after IndexWords(%s), want 3", got, maintenance)
+				}
+			}
+
+			if err := b.repository.IndexWords(ctx, "vacuum"); err == nil {
+				t.Errorf("IndexWords(vacuum) error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,805 @@
+// Package memory keeps a dictionary in memory, so that it can be searched without a database.
+//
+// Usage:
+//
+//	dictionary, err := memory.Load(ctx, data.English, "english")
+//	matches, err := dictionary.SearchWords(ctx, types.GetDescribedWordsInput{Tokens: "a young dog"})
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+	"github.com/oleoneto/redic/app/pkg/highlight"
+	"github.com/oleoneto/redic/app/pkg/morphy"
+	rank "github.com/oleoneto/redic/app/pkg/ranking"
+	"github.com/oleoneto/redic/app/pkg/search"
+)
+
+type word struct {
+	id           int
+	text         string
+	partOfSpeech string
+	language     string
+	synsets      []string // ids of the synsets it is a member of
+}
+
+type synset struct {
+	id           string
+	ili          string
+	partOfSpeech string
+	definition   string
+	language     string
+	source       string
+	lexfile      string
+	examples     []types.Example
+	members      [2][]*word // implicit members, then explicit ones, in order
+}
+
+// kind - the members of the synset that are explicit, or those that are not.
+func kind(explicit bool) int {
+	if explicit {
+		return 1
+	}
+	return 0
+}
+
+// entry - a member of a synset, along with what is known about it.
+type entry struct {
+	word     *word
+	synset   *synset
+	explicit bool
+	position int
+}
+
+// entries - the members of the synset, implicit ones first.
+func (s *synset) entries() []entry {
+	var entries []entry
+
+	for _, explicit := range []bool{false, true} {
+		for position, w := range s.members[kind(explicit)] {
+			entries = append(entries, entry{word: w, synset: s, explicit: explicit, position: position})
+		}
+	}
+
+	return entries
+}
+
+// Dictionary - a DictionaryBackend kept in memory, with an inverted index of its own.
+// It is safe for concurrent use.
+type Dictionary struct {
+	mu sync.RWMutex
+
+	words        map[int]*word
+	keys         map[[3]string]*word // by text, part of speech and language
+	texts        map[string][]*word  // by text, across parts of speech and languages
+	synsets      map[string]*synset
+	interlingual map[string][]string         // synset ids, by interlingual index
+	relations    map[string][]types.Relation // by source synset
+	inverse      map[string][]types.Relation // by target synset
+	sources      map[string]types.Source
+	index        *index
+	lastId       int
+}
+
+// Explicit interface conformance check
+var _ protocols.DictionaryBackend = (*Dictionary)(nil)
+
+// NewDictionary - returns an empty dictionary.
+func NewDictionary() *Dictionary {
+	return &Dictionary{
+		words:        map[int]*word{},
+		keys:         map[[3]string]*word{},
+		texts:        map[string][]*word{},
+		synsets:      map[string]*synset{},
+		interlingual: map[string][]string{},
+		relations:    map[string][]types.Relation{},
+		inverse:      map[string][]types.Relation{},
+		sources:      map[string]types.Source{},
+		index:        newIndex(),
+	}
+}
+
+// NewWords - Adds words to the dictionary.
+//
+// Words that share a definition are stored as members of the same synset.
+func (d *Dictionary) NewWords(ctx context.Context, words []types.NewWordInput) error {
+	return d.NewSynsets(ctx, types.Synsets(words))
+}
+
+// NewSynsets - Adds synsets, their members, examples and relations to the dictionary,
+// indexing their members as they are added.
+func (d *Dictionary) NewSynsets(ctx context.Context, synsets []types.NewSynsetInput) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, item := range synsets {
+		d.writeSynset(item)
+	}
+
+	return nil
+}
+
+// writeSynset - creates or refreshes the synset. Members of the same kind (explicit or not),
+// examples and outgoing relations are replaced wholesale.
+func (d *Dictionary) writeSynset(item types.NewSynsetInput) {
+	s, ok := d.synsets[item.Id]
+	if !ok {
+		s = &synset{id: item.Id}
+		d.synsets[item.Id] = s
+	}
+
+	d.unindex(s)
+	d.link(s, item.ILI)
+
+	s.partOfSpeech = item.PartOfSpeech
+	s.definition = item.Definition()
+	s.language = types.LexiconLanguage(item.Language)
+	s.source = item.Source
+	s.lexfile = item.Lexfile
+	s.examples = slices.Clone(item.Examples)
+
+	members, others := kind(item.Explicit), kind(!item.Explicit)
+
+	for _, w := range s.members[members] {
+		w.leave(s.id)
+	}
+
+	s.members[members] = nil
+
+	for _, member := range item.Members {
+		w := d.word(member, item.PartOfSpeech, s.language)
+
+		/* A word is a member of a synset once, of the kind it was last added as */
+		if slices.Contains(w.synsets, s.id) {
+			s.members[others] = slices.DeleteFunc(s.members[others], func(m *word) bool { return m == w })
+			s.members[members] = slices.DeleteFunc(s.members[members], func(m *word) bool { return m == w })
+		} else {
+			w.synsets = append(w.synsets, s.id)
+		}
+
+		s.members[members] = append(s.members[members], w)
+	}
+
+	d.relate(s.id, item.Relations)
+	d.reindex(s)
+}
+
+// word - returns the word, adding it if it is not yet in the dictionary.
+func (d *Dictionary) word(text, partOfSpeech, language string) *word {
+	key := [3]string{text, partOfSpeech, language}
+
+	if w, ok := d.keys[key]; ok {
+		return w
+	}
+
+	d.lastId++
+
+	w := &word{id: d.lastId, text: text, partOfSpeech: partOfSpeech, language: language}
+	d.words[w.id] = w
+	d.keys[key] = w
+	d.texts[text] = append(d.texts[text], w)
+
+	return w
+}
+
+// leave - removes the word from the synset.
+func (w *word) leave(synset string) {
+	w.synsets = slices.DeleteFunc(w.synsets, func(id string) bool { return id == synset })
+}
+
+// link - files the synset under its interlingual index, if it has one.
+func (d *Dictionary) link(s *synset, ili string) {
+	if s.ili == ili {
+		return
+	}
+
+	if s.ili != "" {
+		if d.interlingual[s.ili] = slices.DeleteFunc(d.interlingual[s.ili], func(id string) bool { return id == s.id }); len(d.interlingual[s.ili]) == 0 {
+			delete(d.interlingual, s.ili)
+		}
+	}
+
+	if s.ili = ili; ili != "" {
+		d.interlingual[ili] = append(d.interlingual[ili], s.id)
+	}
+}
+
+// relate - replaces the outgoing relations of the synset.
+func (d *Dictionary) relate(source string, relations []types.Relation) {
+	for _, relation := range d.relations[source] {
+		d.inverse[relation.Target] = slices.DeleteFunc(d.inverse[relation.Target], func(r types.Relation) bool { return r == relation })
+	}
+
+	delete(d.relations, source)
+
+	for _, relation := range relations {
+		d.addRelation(relation)
+	}
+}
+
+func (d *Dictionary) addRelation(relation types.Relation) {
+	if slices.Contains(d.relations[relation.Source], relation) {
+		return
+	}
+
+	d.relations[relation.Source] = append(d.relations[relation.Source], relation)
+	d.inverse[relation.Target] = append(d.inverse[relation.Target], relation)
+}
+
+func (d *Dictionary) reindex(s *synset) {
+	examples := strings.Join(helpers.Map(s.examples, func(_ int, e types.Example) string { return e.Text }), "\n")
+
+	for _, e := range s.entries() {
+		d.index.add(member{word: e.word.id, synset: s.id}, [fields]string{e.word.text, s.definition, examples})
+	}
+}
+
+func (d *Dictionary) unindex(s *synset) {
+	for _, e := range s.entries() {
+		d.index.remove(member{word: e.word.id, synset: s.id})
+	}
+}
+
+// NewRelations - Adds typed edges between synsets.
+func (d *Dictionary) NewRelations(ctx context.Context, relations []types.Relation) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, relation := range relations {
+		d.addRelation(relation)
+	}
+
+	return nil
+}
+
+// explanation - a definition along with the word it was found under.
+type explanation struct {
+	types.Definition
+	Word string
+}
+
+// GetWordExplanation - Looks for the given word in the dictionary and returns its definition(s).
+//
+// Inflected words are looked up by their base forms the way DictionaryRepository does
+// (i.e geese → goose), unless the search is verbatim.
+func (d *Dictionary) GetWordExplanation(ctx context.Context, data types.GetWordDefinitionsInput) (types.WordDefinitions, error) {
+	var res = types.WordDefinitions{Definitions: []types.Definition{}, Word: data.Word}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	definitions := d.wordExplanations(data, []morphy.Lemma{{Word: data.Word}})
+
+	if !data.Verbatim && types.IsEnglish(data.Language) {
+		var defined = map[types.PartOfSpeech]bool{}
+		var synsets = map[string]bool{}
+
+		for _, definition := range definitions {
+			defined[definition.PartOfSpeech] = true
+			synsets[definition.SynsetId] = true
+		}
+
+		lemmas := helpers.Filter(morphy.Lemmas(data.Word, data.PartOfSpeech), func(_ int, lemma morphy.Lemma) bool {
+			if lemma.PartOfSpeech == types.Adjective1 {
+				return lemma.Irregular || !(defined[types.Adjective1] || defined[types.Adjective2])
+			}
+			return lemma.Irregular || !defined[lemma.PartOfSpeech]
+		})
+
+		for _, definition := range d.wordExplanations(data, lemmas) {
+			if !synsets[definition.SynsetId] {
+				definition.Lemma = definition.Word
+				definitions = append(definitions, definition)
+			}
+		}
+	}
+
+	for _, definition := range definitions {
+		res.Definitions = append(res.Definitions, definition.Definition)
+	}
+
+	return res, ctx.Err()
+}
+
+// wordExplanations - Returns the definitions of any of the given words, with their examples.
+func (d *Dictionary) wordExplanations(data types.GetWordDefinitionsInput, words []morphy.Lemma) []explanation {
+	var res = []explanation{}
+	var seen = map[member]bool{}
+
+	for _, lemma := range words {
+		for _, w := range d.texts[lemma.Word] {
+			if !partOfSpeech(w, lemma.PartOfSpeech) {
+				continue
+			}
+
+			if data.PartOfSpeech != "" && data.PartOfSpeech != types.ALL && w.partOfSpeech != string(data.PartOfSpeech) {
+				continue
+			}
+
+			for _, id := range w.synsets {
+				s := d.synsets[id]
+
+				if seen[member{word: w.id, synset: id}] || (data.Language != "" && !types.MatchesLanguage(s.language, data.Language)) {
+					continue
+				}
+
+				seen[member{word: w.id, synset: id}] = true
+
+				res = append(res, explanation{
+					Word: w.text,
+					Definition: types.Definition{
+						SynsetId:     s.id,
+						ILI:          s.ili,
+						PartOfSpeech: types.PartOfSpeech(w.partOfSpeech),
+						Definition:   d.gloss(s),
+						Examples:     s.examples,
+						Explicit:     slices.Contains(s.members[kind(true)], w),
+						Language:     s.language,
+						Lexfile:      s.lexfile,
+					},
+				})
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i].Definition, res[j].Definition
+		if a.Language != b.Language {
+			return a.Language < b.Language
+		}
+		if a.PartOfSpeech != b.PartOfSpeech {
+			return a.PartOfSpeech < b.PartOfSpeech
+		}
+		return a.SynsetId < b.SynsetId
+	})
+
+	return res
+}
+
+// partOfSpeech - whether the word is of the part of speech a lemma was found for, any when empty.
+func partOfSpeech(w *word, pos types.PartOfSpeech) bool {
+	switch pos {
+	case "":
+		return true
+	case types.Adjective1, types.Adjective2:
+		return w.partOfSpeech == string(types.Adjective1) || w.partOfSpeech == string(types.Adjective2)
+	}
+
+	return w.partOfSpeech == string(pos)
+}
+
+// gloss - the definition of the synset. Synsets without a gloss of their own borrow one
+// through their interlingual index.
+func (d *Dictionary) gloss(s *synset) string {
+	if s.definition != "" || s.ili == "" {
+		return s.definition
+	}
+
+	var ids = slices.Clone(d.interlingual[s.ili])
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		if definition := d.synsets[id].definition; definition != "" {
+			return definition
+		}
+	}
+
+	return ""
+}
+
+// match - an entry that matched a search, with how it was ranked.
+type match struct {
+	entry
+	score types.Score
+}
+
+// SearchWords - Looks for all matching words for the provided word context, best matches first.
+//
+// Descriptions use the syntax of the search package and are ranked as they are in SQLite,
+// with BM25 weighing each field apart and the priors of the ranking package.
+// Given a target language, matches are translated into the words of that language
+// that share their interlingual index.
+func (d *Dictionary) SearchWords(ctx context.Context, data types.GetDescribedWordsInput) (types.WordMatches, error) {
+	var res = types.WordMatches{ProvidedDescriptions: data.Tokens, MatchingWords: []types.MatchingWord{}}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var query search.Expr
+
+	if data.Tokens != "" {
+		var err error
+		if query, err = search.Parse(data.Tokens); err != nil {
+			return res, err
+		}
+
+		if types.IsEnglish(data.Language) {
+			query, res.Lemmas, _ = morphy.ExpandQuery(query, d.known)
+		}
+	}
+
+	filter := func(e entry) bool {
+		switch {
+		case data.PartOfSpeech != "" && e.word.partOfSpeech != string(data.PartOfSpeech):
+			return false
+		case data.Language != "" && !types.MatchesLanguage(e.synset.language, data.Language):
+			return false
+		case data.Category != "" && e.synset.lexfile != data.Category:
+			return false
+		}
+		return true
+	}
+
+	var matches []match
+	var ranking = data.Weights()
+
+	if query != nil {
+		terms := search.Terms(query)
+
+		for document, relevance := range compile(query).evaluate(d.index, weights{ranking.Word, ranking.Definition, ranking.Examples}) {
+			if e := d.entry(d.index.members[document]); filter(e) {
+				score := rank.Score(ranking, terms, rank.Match{
+					Relevance: relevance,
+					Position:  e.position,
+					Senses:    len(e.word.synsets),
+					Word:      e.word.text,
+					Gloss:     e.synset.definition,
+				})
+
+				matches = append(matches, match{entry: e, score: score})
+			}
+		}
+
+		res.Ranking = &ranking
+	} else {
+		for _, s := range d.synsets {
+			for _, e := range s.entries() {
+				if filter(e) {
+					matches = append(matches, match{entry: e})
+				}
+			}
+		}
+	}
+
+	matches = best(matches)
+
+	if data.TargetLanguage != "" && data.TargetLanguage != data.Language {
+		matches = best(d.translate(matches[:min(len(matches), rank.Candidates)], data.TargetLanguage))
+	}
+
+	/* The page starts after the cursor, and one match past it tells whether there are more */
+	var start, size = 0, data.PageSize()
+	if data.After != nil {
+		start = sort.Search(len(matches), func(i int) bool { return matches[i].key().Compare(*data.After) > 0 })
+	}
+
+	var phrases [][]string
+	if query != nil {
+		phrases = search.Phrases(query)
+	}
+
+	for _, m := range matches[start:min(len(matches), start+size+1)] {
+		match := types.MatchingWord{
+			Id:           m.word.id,
+			SynsetId:     m.synset.id,
+			Word:         m.word.text,
+			PartOfSpeech: types.PartOfSpeech(m.word.partOfSpeech),
+			Definition:   m.synset.definition,
+			Language:     m.synset.language,
+			Lexfile:      m.synset.lexfile,
+		}
+
+		if data.Tokens != "" {
+			match.Score = &m.score
+			highlight.Match(&match, phrases, helpers.Map(m.synset.examples, func(_ int, e types.Example) string { return e.Text }))
+		}
+
+		res.MatchingWords = append(res.MatchingWords, match)
+	}
+
+	res.Page(nil, size)
+
+	return res, ctx.Err()
+}
+
+// known - which of the words are in the dictionary, in any part of speech or language.
+func (d *Dictionary) known(words []string) (map[string]bool, error) {
+	var known = map[string]bool{}
+
+	for _, w := range words {
+		known[w] = len(d.texts[w]) != 0
+	}
+
+	return known, nil
+}
+
+func (d *Dictionary) entry(m member) entry {
+	w, s := d.words[m.word], d.synsets[m.synset]
+
+	for _, explicit := range []bool{false, true} {
+		if position := slices.Index(s.members[kind(explicit)], w); position != -1 {
+			return entry{word: w, synset: s, explicit: explicit, position: position}
+		}
+	}
+
+	return entry{word: w, synset: s}
+}
+
+// translate - the words of the target language that share an interlingual index
+// with any of the matches, each scored as its best match.
+func (d *Dictionary) translate(matches []match, target string) []match {
+	var translations = map[member]*match{}
+
+	for _, m := range matches {
+		if m.synset.ili == "" {
+			continue
+		}
+
+		for _, id := range d.interlingual[m.synset.ili] {
+			t := d.synsets[id]
+			if !types.MatchesLanguage(t.language, target) {
+				continue
+			}
+
+			for _, e := range t.entries() {
+				key := member{word: e.word.id, synset: t.id}
+
+				if known, ok := translations[key]; !ok {
+					translations[key] = &match{entry: e, score: m.score}
+				} else if m.score.Total > known.score.Total {
+					known.score = m.score
+				}
+			}
+		}
+	}
+
+	var res = make([]match, 0, len(translations))
+	for _, m := range translations {
+		res = append(res, *m)
+	}
+
+	return res
+}
+
+// best - the matches, those that score highest first, by word when they tie.
+func best(matches []match) []match {
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].key().Compare(matches[j].key()) < 0
+	})
+
+	return matches
+}
+
+// key - where the match is ordered among others, as its types.MatchingWord would be.
+func (m match) key() types.Cursor {
+	return types.Cursor{Rank: m.score.Total, Word: m.word.text, Id: m.word.id, SynsetId: m.synset.id}
+}
+
+// GetRelatedWords - Looks for words whose synsets are linked to any synset of the given word.
+//
+// Relations are followed in both directions, so hypernyms and hyponyms are returned alike.
+func (d *Dictionary) GetRelatedWords(ctx context.Context, data types.GetRelatedWordsInput) (types.RelatedWords, error) {
+	var res = types.RelatedWords{Word: data.Word, Relations: []types.RelatedWord{}}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	related := func(relation types.Relation, id string, inverse bool) {
+		if data.RelationType != "" && relation.Type != data.RelationType {
+			return
+		}
+
+		t, ok := d.synsets[id]
+		if !ok {
+			return
+		}
+
+		for _, e := range t.entries() {
+			res.Relations = append(res.Relations, types.RelatedWord{
+				Word:         e.word.text,
+				PartOfSpeech: types.PartOfSpeech(e.word.partOfSpeech),
+				Definition:   t.definition,
+				Relation:     relation.Type,
+				Inverse:      inverse,
+			})
+		}
+	}
+
+	for _, w := range d.texts[data.Word] {
+		if data.PartOfSpeech != "" && data.PartOfSpeech != types.ALL && w.partOfSpeech != string(data.PartOfSpeech) {
+			continue
+		}
+
+		for _, id := range w.synsets {
+			for _, relation := range d.relations[id] {
+				related(relation, relation.Target, false)
+			}
+
+			for _, relation := range d.inverse[id] {
+				related(relation, relation.Source, true)
+			}
+		}
+	}
+
+	sort.SliceStable(res.Relations, func(i, j int) bool {
+		a, b := res.Relations[i], res.Relations[j]
+		if a.Relation != b.Relation {
+			return a.Relation < b.Relation
+		}
+		if a.Inverse != b.Inverse {
+			return !a.Inverse
+		}
+		return a.Word < b.Word
+	})
+
+	return res, ctx.Err()
+}
+
+// ExportSynsets - Reads back every synset of the dictionary with its members, examples and relations.
+//
+// Members that are explicit for a synset are emitted as a synset of their own,
+// the way they are grouped when imported.
+func (d *Dictionary) ExportSynsets(ctx context.Context, emit func(types.NewSynsetInput) error) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var ids = make([]string, 0, len(d.synsets))
+	for id := range d.synsets {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		s := d.synsets[id]
+
+		for _, explicit := range []bool{false, true} {
+			if len(s.members[kind(explicit)]) == 0 {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			err := emit(types.NewSynsetInput{
+				Id:           s.id,
+				ILI:          s.ili,
+				PartOfSpeech: s.partOfSpeech,
+				Definitions:  []string{s.definition},
+				Examples:     s.examples,
+				Relations:    d.relations[s.id],
+				Members:      helpers.Map(s.members[kind(explicit)], func(_ int, w *word) string { return w.text }),
+				Explicit:     explicit,
+				Language:     s.language,
+				Lexfile:      s.lexfile,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetSource - Returns the file as of its last ingestion, or a zero source if it was never ingested.
+func (d *Dictionary) GetSource(ctx context.Context, name string) (types.Source, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if source, ok := d.sources[name]; ok {
+		return source, nil
+	}
+
+	return types.Source{Name: name}, ctx.Err()
+}
+
+// GetSourceChecksums - Returns no checksums: a dictionary in memory is loaded once and
+// never re-ingested, so fingerprinting each synset would only slow loading down. A changed
+// file has every one of its synsets written again.
+func (d *Dictionary) GetSourceChecksums(ctx context.Context, name string) (map[string]string, error) {
+	return map[string]string{}, ctx.Err()
+}
+
+// RecordSource - Removes the synsets a file no longer contains and records its new hash.
+//
+// Words left without any synset are removed as well.
+func (d *Dictionary) RecordSource(ctx context.Context, update types.SourceUpdate) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, id := range update.Stale {
+		/* A synset that moved to another file belongs to that file now */
+		if s, ok := d.synsets[id]; ok && s.source == update.Source.Name {
+			d.deleteSynset(s)
+		}
+	}
+
+	/* Only a file ingested before may have dropped members from its synsets */
+	if _, known := d.sources[update.Source.Name]; known {
+		for id, w := range d.words {
+			if len(w.synsets) == 0 {
+				d.deleteWord(id, w)
+			}
+		}
+	}
+
+	update.Source.ImportedAt = time.Now().UTC()
+	d.sources[update.Source.Name] = update.Source
+
+	return nil
+}
+
+func (d *Dictionary) deleteSynset(s *synset) {
+	d.unindex(s)
+	d.relate(s.id, nil)
+	d.link(s, "")
+
+	for _, e := range s.entries() {
+		e.word.leave(s.id)
+	}
+
+	delete(d.synsets, s.id)
+}
+
+func (d *Dictionary) deleteWord(id int, w *word) {
+	delete(d.words, id)
+	delete(d.keys, [3]string{w.text, w.partOfSpeech, w.language})
+
+	if d.texts[w.text] = slices.DeleteFunc(d.texts[w.text], func(other *word) bool { return other == w }); len(d.texts[w.text]) == 0 {
+		delete(d.texts, w.text)
+	}
+}
+
+// SuspendIndexing - Does nothing: words are indexed as they are written, which costs
+// no more than indexing them all at once.
+func (d *Dictionary) SuspendIndexing(ctx context.Context) (func(context.Context) error, error) {
+	return func(context.Context) error { return nil }, ctx.Err()
+}
+
+// IndexWords - Rebuilds the index of every word, whichever the maintenance.
+//
+// Writes keep the index up to date, so this is only needed to compact it
+// once many synsets have been rewritten or removed.
+func (d *Dictionary) IndexWords(ctx context.Context, maintenance types.IndexMaintenance) error {
+	if maintenance != types.RebuildIndex && maintenance != types.OptimizeIndex {
+		return fmt.Errorf("unknown index maintenance %q", maintenance)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.index = newIndex()
+
+	for _, s := range d.synsets {
+		d.reindex(s)
+	}
+
+	return nil
+}
@@ -0,0 +1,324 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+	"github.com/oleoneto/redic/app/pkg/repositories/memory"
+)
+
+var fsys = fstest.MapFS{
+	"english/noun.animal.yaml": {Data: []byte(`02084071-n:
+  definition:
+  - a member of the genus Canis that has been domesticated by man since prehistoric times
+  example:
+  - the dog barked all night
+  hypernym:
+  - 02083346-n
+  members:
+  - dog
+  - domestic dog
+  partOfSpeech: n
+02083346-n:
+  definition:
+  - any of various fissiped mammals with nonretractile claws
+  members:
+  - canine
+  partOfSpeech: n
+02114100-n:
+  definition:
+  - any of various wild canines of the genus Canis, hunting in packs
+  hypernym:
+  - 02083346-n
+  members:
+  - wolf
+  partOfSpeech: n
+02077948-n:
+  definition:
+  - any of several large marine mammals; the sea lion and the walrus
+  members:
+  - seal
+  partOfSpeech: n
+`)},
+	"english/verb.body.yaml": {Data: []byte(`00014742-v:
+  definition:
+  - make a loud noise like a dog, as a dog does
+  members:
+  - bark
+  partOfSpeech: v
+`)},
+}
+
+func load(t *testing.T) *memory.Dictionary {
+	t.Helper()
+
+	dictionary, err := memory.Load(context.Background(), fsys, "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dictionary
+}
+
+func Test_Dictionary_SearchWords(t *testing.T) {
+	dictionary := load(t)
+
+	tests := []struct {
+		name    string
+		input   types.GetDescribedWordsInput
+		want    []string
+		lemmas  map[string][]string
+		invalid bool
+	}{
+		{name: "every term", input: types.GetDescribedWordsInput{Tokens: "genus Canis"}, want: []string{"wolf", "dog", "domestic dog"}},
+		{name: "any term", input: types.GetDescribedWordsInput{Tokens: "walrus OR domesticated"}, want: []string{"seal", "dog", "domestic dog"}},
+		{name: "excluded term", input: types.GetDescribedWordsInput{Tokens: "Canis -wild"}, want: []string{"dog", "domestic dog"}},
+		{name: "phrase", input: types.GetDescribedWordsInput{Tokens: `"sea lion"`}, want: []string{"seal"}},
+		{name: "phrase in order", input: types.GetDescribedWordsInput{Tokens: `"lion sea"`}, want: []string{}},
+		{name: "near", input: types.GetDescribedWordsInput{Tokens: "NEAR(noise dog, 2)"}, want: []string{"bark"}},
+		{name: "too far", input: types.GetDescribedWordsInput{Tokens: "NEAR(loud does, 2)"}, want: []string{}},
+		{name: "hyphenated", input: types.GetDescribedWordsInput{Tokens: "sea-lion"}, want: []string{"seal"}},
+		{name: "prefix", input: types.GetDescribedWordsInput{Tokens: "domestic*"}, want: []string{"domestic dog", "dog"}},
+		{name: "inflection", input: types.GetDescribedWordsInput{Tokens: "hunting wolves"}, want: []string{"wolf"}, lemmas: map[string][]string{"wolves": {"wolf"}}},
+		{name: "part of speech", input: types.GetDescribedWordsInput{Tokens: "dog", PartOfSpeech: types.Verb}, want: []string{"bark"}},
+		{name: "category", input: types.GetDescribedWordsInput{Tokens: "dog", Category: "noun.animal"}, want: []string{"dog", "domestic dog"}},
+		{name: "dangling operator", input: types.GetDescribedWordsInput{Tokens: "walrus OR"}, invalid: true},
+		{name: "unclosed group", input: types.GetDescribedWordsInput{Tokens: "(walrus"}, invalid: true},
+		{name: "no description", input: types.GetDescribedWordsInput{Category: "verb.body"}, want: []string{"bark"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := dictionary.SearchWords(context.Background(), tt.input)
+
+			var invalid *types.QueryError
+			if errors.As(err, &invalid) != tt.invalid {
+				t.Fatalf("SearchWords(%q) error = %v, want a *types.QueryError: %v", tt.input.Tokens, err, tt.invalid)
+			}
+
+			if tt.invalid {
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := helpers.Map(res.MatchingWords, func(_ int, m types.MatchingWord) string { return m.Word }); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchWords(%q) = %v, want %v", tt.input.Tokens, got, tt.want)
+			}
+
+			if !reflect.DeepEqual(res.Lemmas, tt.lemmas) {
+				t.Errorf("SearchWords(%q) lemmas = %v, want %v", tt.input.Tokens, res.Lemmas, tt.lemmas)
+			}
+		})
+	}
+}
+
+func Test_Dictionary_SearchWords_Ranking(t *testing.T) {
+	dictionary := load(t)
+
+	tests := []struct {
+		name    string
+		ranking *types.Ranking
+		want    []string
+	}{
+		{name: "default", want: []string{"dog", "domestic dog", "bark"}},
+		{name: "definitions only", ranking: &types.Ranking{Definition: 1}, want: []string{"bark", "dog", "domestic dog"}},
+		{name: "words only", ranking: &types.Ranking{Word: 1}, want: []string{"dog", "domestic dog", "bark"}},
+		{name: "coverage only", ranking: &types.Ranking{Coverage: 1}, want: []string{"bark", "dog", "domestic dog"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := dictionary.SearchWords(context.Background(), types.GetDescribedWordsInput{Tokens: "dog", Ranking: tt.ranking})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := helpers.Map(res.MatchingWords, func(_ int, m types.MatchingWord) string { return m.Word }); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchWords(dog) = %v, want %v", got, tt.want)
+			}
+
+			if want := (types.GetDescribedWordsInput{Ranking: tt.ranking}).Weights(); res.Ranking == nil || *res.Ranking != want {
+				t.Errorf("SearchWords(dog) ranking = %v, want %v", res.Ranking, want)
+			}
+
+			for _, m := range res.MatchingWords {
+				if m.Score == nil {
+					t.Errorf("SearchWords(dog) = %s without a score", m.Word)
+				}
+			}
+		})
+	}
+}
+
+func Test_Dictionary_SearchWords_Pages(t *testing.T) {
+	dictionary := load(t)
+
+	tests := []struct {
+		name  string
+		input types.GetDescribedWordsInput
+	}{
+		{name: "ranked", input: types.GetDescribedWordsInput{Tokens: "dog"}},
+		{name: "listed", input: types.GetDescribedWordsInput{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all, err := dictionary.SearchWords(context.Background(), tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if all.HasMore || all.Next != nil {
+				t.Errorf("SearchWords(%q) has more than %d matches", tt.input.Tokens, len(all.MatchingWords))
+			}
+
+			/* Pages of one match each add up to the whole, neither skipping nor repeating any */
+			var got []types.MatchingWord
+
+			for input := tt.input; ; {
+				input.Limit = 1
+
+				page, err := dictionary.SearchWords(context.Background(), input)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got = append(got, page.MatchingWords...)
+				if !page.HasMore {
+					break
+				}
+
+				input.After = page.Next
+			}
+
+			if !reflect.DeepEqual(got, all.MatchingWords) {
+				t.Errorf("SearchWords(%q) in pages = %v, want %v", tt.input.Tokens, got, all.MatchingWords)
+			}
+		})
+	}
+}
+
+func Test_Dictionary_GetWordExplanation(t *testing.T) {
+	dictionary := load(t)
+
+	res, err := dictionary.GetWordExplanation(context.Background(), types.GetWordDefinitionsInput{Word: "dogs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Definitions) != 1 {
+		t.Fatalf("GetWordExplanation(dogs) = %d definitions, want 1", len(res.Definitions))
+	}
+
+	want := types.Definition{
+		SynsetId:     "02084071-n",
+		PartOfSpeech: types.Noun,
+		Definition:   "a member of the genus Canis that has been domesticated by man since prehistoric times",
+		Examples:     []types.Example{{Text: "the dog barked all night"}},
+		Language:     types.DefaultLanguage,
+		Lemma:        "dog",
+		Lexfile:      "noun.animal",
+	}
+
+	if got := res.Definitions[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetWordExplanation(dogs) = %+v, want %+v", got, want)
+	}
+}
+
+func Test_Dictionary_GetRelatedWords(t *testing.T) {
+	dictionary := load(t)
+
+	res, err := dictionary.GetRelatedWords(context.Background(), types.GetRelatedWordsInput{Word: "canine"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := helpers.Map(res.Relations, func(_ int, r types.RelatedWord) string { return r.Word })
+	if want := []string{"dog", "domestic dog", "wolf"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRelatedWords(canine) = %v, want %v", got, want)
+	}
+
+	for _, relation := range res.Relations {
+		if relation.Relation != types.Hypernym || !relation.Inverse {
+			t.Errorf("GetRelatedWords(canine) = %+v, want an inverse hypernym", relation)
+		}
+	}
+}
+
+func Test_Dictionary_RecordSource(t *testing.T) {
+	ctx := context.Background()
+	dictionary := load(t)
+
+	source := types.Source{Name: "noun.animal.yaml", Hash: "a"}
+	if err := dictionary.RecordSource(ctx, types.SourceUpdate{Source: source}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dictionary.RecordSource(ctx, types.SourceUpdate{Source: source, Stale: []string{"02114100-n", "00014742-v"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for word, want := range map[string]int{"wolf": 0, "bark": 1} {
+		res, err := dictionary.SearchWords(ctx, types.GetDescribedWordsInput{Tokens: word})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := len(res.MatchingWords); got != want {
+			t.Errorf("SearchWords(%s) = %d matches after %s went stale, want %d", word, got, source.Name, want)
+		}
+	}
+
+	if err := dictionary.IndexWords(ctx, types.RebuildIndex); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := dictionary.SearchWords(ctx, types.GetDescribedWordsInput{Tokens: "canis"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(res.MatchingWords); got != 2 {
+		t.Errorf("SearchWords(canis) = %d matches after reindexing, want 2", got)
+	}
+}
+
+func Test_Dictionary_IndexWords(t *testing.T) {
+	ctx := context.Background()
+	dictionary := load(t)
+
+	tests := []struct {
+		maintenance types.IndexMaintenance
+		wantErr     bool
+	}{
+		{maintenance: types.RebuildIndex},
+		{maintenance: types.OptimizeIndex},
+		{maintenance: "vacuum", wantErr: true},
+		{maintenance: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.maintenance), func(t *testing.T) {
+			if err := dictionary.IndexWords(ctx, tt.maintenance); (err != nil) != tt.wantErr {
+				t.Fatalf("IndexWords(%q) error = %v, wantErr %v", tt.maintenance, err, tt.wantErr)
+			}
+
+			res, err := dictionary.SearchWords(ctx, types.GetDescribedWordsInput{Tokens: "canis"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := len(res.MatchingWords); got != 3 {
+				t.Errorf("SearchWords(canis) = %d matches after IndexWords(%q), want 3", got, tt.maintenance)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package memory
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/oleoneto/redic/app/pkg/ranking"
+)
+
+// BM25 parameters, the ones FTS5 uses.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Fields of a document, in the order they are indexed: the word, its definition and
+// the examples of its synset.
+const fields = 3
+
+// Positions carry the field of a term in their upper bits.
+const fieldShift = 24
+
+// weights - how much a match in each field counts.
+type weights [fields]float64
+
+// hits - how often a phrase occurs in each field of a document.
+type hits [fields]int
+
+// member - a word of a synset, the unit the dictionary is searched by.
+type member struct {
+	word   int
+	synset string
+}
+
+// occurrence - a term at a position of a document.
+type occurrence struct{ document, position int32 }
+
+// index - an inverted index of the words, definitions and examples of synset members,
+// each indexed as a document of its own.
+//
+// Members are tokenized the way FTS5's default tokenizer does, so queries match
+// alike in memory and in SQLite. Removed documents are only marked as such, and
+// their occurrences skipped, until the index is rebuilt.
+type index struct {
+	postings map[string][]occurrence // term → where it occurs, by document and position
+	docs     map[member]int32        // member → its document
+	members  []member                // document → its member
+	lengths  []int32                 // document → number of terms, or -1 once removed
+	live     int                     // number of documents that were not removed
+	total    int                     // number of terms of those documents
+
+	mu     sync.Mutex
+	sorted []string // every term, in order, for prefix lookups
+	stale  bool     // whether terms were added since sorted was built
+}
+
+func newIndex() *index {
+	return &index{
+		postings: map[string][]occurrence{},
+		docs:     map[member]int32{},
+	}
+}
+
+// add - indexes the fields of a member as a new document. Each field starts a range
+// of positions of its own, so that phrases never match across fields.
+func (ix *index) add(m member, values [fields]string) {
+	ix.remove(m)
+
+	var document = int32(len(ix.members))
+	var length int32
+
+	for field, value := range values {
+		for i, term := range ranking.Tokenize(value) {
+			if _, ok := ix.postings[term]; !ok {
+				ix.stale = true
+			}
+
+			ix.postings[term] = append(ix.postings[term], occurrence{document, int32(field)<<fieldShift | int32(i)})
+			length++
+		}
+	}
+
+	ix.docs[m] = document
+	ix.members = append(ix.members, m)
+	ix.lengths = append(ix.lengths, length)
+	ix.live++
+	ix.total += int(length)
+}
+
+// remove - drops a member from the index, if it is in it.
+func (ix *index) remove(m member) {
+	document, ok := ix.docs[m]
+	if !ok {
+		return
+	}
+
+	ix.live--
+	ix.total -= int(ix.lengths[document])
+	ix.lengths[document] = -1
+
+	delete(ix.docs, m)
+}
+
+// expand - the terms that start with the prefix.
+//
+// Terms are only sorted for the first lookup after they change, so that writes stay cheap.
+// Lookups run concurrently, so sorting them is guarded by a lock of its own.
+func (ix *index) expand(prefix string) []string {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if ix.stale {
+		ix.sorted = make([]string, 0, len(ix.postings))
+		for term := range ix.postings {
+			ix.sorted = append(ix.sorted, term)
+		}
+
+		sort.Strings(ix.sorted)
+		ix.stale = false
+	}
+
+	var i = sort.SearchStrings(ix.sorted, prefix)
+	var j = i
+
+	for j < len(ix.sorted) && strings.HasPrefix(ix.sorted[j], prefix) {
+		j++
+	}
+
+	return ix.sorted[i:j]
+}
+
+// occurrences - where any of the terms occur, by document and position.
+func (ix *index) occurrences(terms []string) []occurrence {
+	if len(terms) == 1 {
+		return ix.postings[terms[0]]
+	}
+
+	var res []occurrence
+	for _, term := range terms {
+		res = append(res, ix.postings[term]...)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return before(res[i], res[j]) })
+
+	return res
+}
+
+// phrase - how often the terms occur one after another in each field of every document
+// that has them. The last term is a prefix when prefix is set.
+func (ix *index) phrase(terms []string, prefix bool) map[int32]hits {
+	return count(ix.starts(terms, prefix))
+}
+
+// starts - where the terms occur one after another, in order, in documents that were not removed.
+// The last term is a prefix when prefix is set.
+func (ix *index) starts(terms []string, prefix bool) []occurrence {
+	var res []occurrence
+	var lists = make([][]occurrence, len(terms))
+
+	for i, term := range terms {
+		alternatives := []string{term}
+		if prefix && i == len(terms)-1 {
+			alternatives = ix.expand(term)
+		}
+
+		if lists[i] = ix.occurrences(alternatives); len(lists[i]) == 0 {
+			return res
+		}
+	}
+
+	for _, o := range lists[0] {
+		if ix.lengths[o.document] >= 0 && follows(lists[1:], o) {
+			res = append(res, o)
+		}
+	}
+
+	return res
+}
+
+// count - how many of the occurrences are in each field of every document.
+func count(occurrences []occurrence) map[int32]hits {
+	var res = map[int32]hits{}
+
+	for _, o := range occurrences {
+		h := res[o.document]
+		h[o.position>>fieldShift]++
+		res[o.document] = h
+	}
+
+	return res
+}
+
+// follows - whether each list has an occurrence in turn after the given one.
+func follows(lists [][]occurrence, o occurrence) bool {
+	for i, list := range lists {
+		next := occurrence{o.document, o.position + int32(i) + 1}
+
+		j := sort.Search(len(list), func(j int) bool { return !before(list[j], next) })
+		if j == len(list) || list[j] != next {
+			return false
+		}
+	}
+
+	return true
+}
+
+func before(a, b occurrence) bool {
+	if a.document != b.document {
+		return a.document < b.document
+	}
+	return a.position < b.position
+}
+
+// score - the BM25 relevance of every document for a phrase, given how often each has it
+// in every field. As in FTS5, a phrase occurs as often in a document as the weighted sum
+// of its hits in each field. Higher is better.
+func (ix *index) score(res map[int32]hits, w weights) map[int32]float64 {
+	var scores = make(map[int32]float64, len(res))
+
+	var n = float64(ix.live)
+	if n == 0 {
+		return scores
+	}
+
+	/* As in FTS5, rare phrases weigh more, but never less than nothing */
+	idf := math.Log((n - float64(len(res)) + 0.5) / (float64(len(res)) + 0.5))
+	if idf <= 0 {
+		idf = 1e-6
+	}
+
+	average := float64(ix.total) / n
+
+	for document, h := range res {
+		var tf float64
+		for field, frequency := range h {
+			tf += w[field] * float64(frequency)
+		}
+
+		scores[document] = idf * (tf * (k1 + 1)) / (tf + k1*(1-b+b*float64(ix.lengths[document])/average))
+	}
+
+	return scores
+}
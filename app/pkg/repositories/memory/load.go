@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/ingestion"
+	"github.com/oleoneto/redic/app/pkg/parsers"
+)
+
+// Load - reads every dictionary file of a directory into a new dictionary,
+// in any of the formats redic imports. Files are recorded as sources, as they are
+// when imported into a database.
+//
+// Usage:
+//
+//	dictionary, err := memory.Load(ctx, data.English, "english")
+//	dictionary, err := memory.Load(ctx, os.DirFS("/usr/share/wordnet"), ".")
+func Load(ctx context.Context, fsys fs.FS, dir string) (*Dictionary, error) {
+	var dictionary = NewDictionary()
+
+	opener := func(name string) (io.ReadCloser, error) { return fsys.Open(name) }
+	parser := parsers.NewAutoParser(func(dir string) ([]fs.DirEntry, error) { return fs.ReadDir(fsys, dir) }, opener)
+
+	files := parser.LoadFiles(ctx, dir)
+	if len(files) == 0 {
+		return dictionary, fmt.Errorf("no dictionary files found in %s", dir)
+	}
+
+	report, err := ingestion.NewPipeline(parser, writer{dictionary}, ingestion.Options{}).Run(ctx, dir, files)
+	if err != nil {
+		return dictionary, err
+	}
+
+	if report.Failed() {
+		return dictionary, errors.New(report.Errors[0])
+	}
+
+	return dictionary, nil
+}
+
+// writer - lets the ingestion pipeline write into a dictionary.
+type writer struct{ *Dictionary }
+
+func (w writer) CreateSynsets(ctx context.Context, synsets []types.NewSynsetInput) error {
+	return w.NewSynsets(ctx, synsets)
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/oleoneto/redic/app/pkg/ranking"
+	"github.com/oleoneto/redic/app/pkg/search"
+)
+
+// expression - a parsed search query, as the index evaluates it. Evaluating an expression
+// scores every document of the index it matches with BM25, weighing each field apart,
+// higher being better.
+type expression interface {
+	evaluate(ix *index, w weights) map[int32]float64
+}
+
+type (
+	phrase struct {
+		terms  []string
+		prefix bool
+	}
+
+	and struct{ left, right expression }
+	or  struct{ left, right expression }
+	not struct{ left, right expression }
+
+	near struct {
+		phrases  []phrase
+		distance int32
+	}
+)
+
+func (p phrase) evaluate(ix *index, w weights) map[int32]float64 {
+	return ix.score(ix.phrase(p.terms, p.prefix), w)
+}
+
+// Documents must match both sides, and are scored by both.
+func (e and) evaluate(ix *index, w weights) map[int32]float64 {
+	var scores = map[int32]float64{}
+	var right = e.right.evaluate(ix, w)
+
+	for document, score := range e.left.evaluate(ix, w) {
+		if other, ok := right[document]; ok {
+			scores[document] = score + other
+		}
+	}
+
+	return scores
+}
+
+// Documents may match either side, and are scored by both.
+func (e or) evaluate(ix *index, w weights) map[int32]float64 {
+	var scores = e.left.evaluate(ix, w)
+
+	for document, score := range e.right.evaluate(ix, w) {
+		scores[document] += score
+	}
+
+	return scores
+}
+
+func (e not) evaluate(ix *index, w weights) map[int32]float64 {
+	var scores = e.left.evaluate(ix, w)
+
+	for document := range e.right.evaluate(ix, w) {
+		delete(scores, document)
+	}
+
+	return scores
+}
+
+// Documents must have every phrase in one field, with at most distance terms between
+// each and the others, and are scored by each phrase where it is near the others.
+func (e near) evaluate(ix *index, w weights) map[int32]float64 {
+	var lists = make([][]occurrence, len(e.phrases))
+	for i, p := range e.phrases {
+		lists[i] = ix.starts(p.terms, p.prefix)
+	}
+
+	var scores map[int32]float64
+
+	for i := range e.phrases {
+		var nearby []occurrence
+
+		for _, o := range lists[i] {
+			if e.around(lists, i, o) {
+				nearby = append(nearby, o)
+			}
+		}
+
+		phrase := ix.score(count(nearby), w)
+
+		if scores == nil {
+			scores = phrase
+			continue
+		}
+
+		for document, score := range scores {
+			if other, ok := phrase[document]; ok {
+				scores[document] = score + other
+			} else {
+				delete(scores, document)
+			}
+		}
+	}
+
+	return scores
+}
+
+// around - whether every other phrase occurs near an occurrence of the i-th one, in the same field:
+// after it, starting at most distance terms past its end, or before it, ending at most distance terms ahead of it.
+func (e near) around(lists [][]occurrence, i int, o occurrence) bool {
+	var field = o.position >> fieldShift << fieldShift
+
+	for j, list := range lists {
+		if j == i {
+			continue
+		}
+
+		from := occurrence{o.document, max(field, o.position-int32(len(e.phrases[j].terms))-e.distance)}
+		to := occurrence{o.document, min(field|(1<<fieldShift-1), o.position+int32(len(e.phrases[i].terms))+e.distance)}
+
+		k := sort.Search(len(list), func(k int) bool { return !before(list[k], from) })
+		if k == len(list) || before(to, list[k]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compile - the expression the index evaluates for a parsed query.
+func compile(e search.Expr) expression {
+	switch e := e.(type) {
+	case search.Phrase:
+		return compilePhrase(e)
+	case search.Any:
+		var res = compile(e.Alternatives[0])
+		for _, alternative := range e.Alternatives[1:] {
+			res = or{res, compile(alternative)}
+		}
+		return res
+	case search.All:
+		var res = compile(e.Required[0])
+		for _, required := range e.Required[1:] {
+			res = and{res, compile(required)}
+		}
+		for _, excluded := range e.Excluded {
+			res = not{res, compile(excluded)}
+		}
+		return res
+	case search.Near:
+		/* Distances past the positions of a field span every one of them */
+		var res = near{distance: int32(min(e.Distance, 1<<fieldShift))}
+		for _, p := range e.Phrases {
+			res.phrases = append(res.phrases, compilePhrase(p))
+		}
+		return res
+	}
+
+	return nil
+}
+
+func compilePhrase(p search.Phrase) phrase {
+	return phrase{terms: ranking.Tokenize(strings.Join(p.Words, " ")), prefix: p.Prefix}
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oleoneto/redic/app/pkg/helpers"
+	"github.com/oleoneto/redic/app/pkg/morphy"
+	dbsql "github.com/oleoneto/redic/app/pkg/repositories/sql"
+	"github.com/oleoneto/redic/app/pkg/search"
+)
+
+// lemmatizeQuery - Extends every inflected word of a parsed query with its base forms
+// that are in the dictionary, i.e `barking dogs` → `barking (dogs OR "dog")`.
+//
+// Returns the query along with the lemmas of each word that was extended.
+func (repo *DictionaryRepository) lemmatizeQuery(ctx context.Context, query search.Expr) (search.Expr, map[string][]string, error) {
+	return morphy.ExpandQuery(query, func(candidates []string) (map[string]bool, error) {
+		var known = map[string]bool{}
+
+		q := dbsql.NewQuery(repo.dialect)
+		args := helpers.Map(candidates, func(_ int, word string) any { return word })
+
+		r, err := repo._db.QueryContext(ctx, fmt.Sprintf(`SELECT DISTINCT text FROM words WHERE text IN (%s)`, q.BindAll(args...)), q.Args()...)
+		if err != nil {
+			return known, err
+		}
+		defer r.Close()
+
+		for r.Next() {
+			var word string
+			if err := r.Scan(&word); err != nil {
+				return known, err
+			}
+			known[word] = true
+		}
+
+		return known, r.Err()
+	})
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+	dbsql "github.com/oleoneto/redic/app/pkg/repositories/sql"
+)
+
+// GetSource - Returns the file as of its last ingestion, or a zero source if it was never ingested.
+func (repo *DictionaryRepository) GetSource(ctx context.Context, name string) (types.Source, error) {
+	var source = types.Source{Name: name}
+
+	var q = dbsql.NewQuery(repo.dialect)
+
+	row := repo._db.QueryRowContext(ctx, `SELECT hash, synsets, imported_at FROM sources WHERE name = `+q.Bind(name), q.Args()...)
+
+	err := row.Scan(&source.Hash, &source.Synsets, &source.ImportedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return source, nil
+	}
+
+	return source, err
+}
+
+// GetSourceChecksums - Returns the checksum of every synset last ingested from the file, by synset id.
+func (repo *DictionaryRepository) GetSourceChecksums(ctx context.Context, name string) (map[string]string, error) {
+	var checksums = map[string]string{}
+
+	var q = dbsql.NewQuery(repo.dialect)
+
+	r, err := repo._db.QueryContext(ctx, `SELECT id, COALESCE(checksum, '') FROM synsets WHERE source = `+q.Bind(name), q.Args()...)
+	if err != nil {
+		return checksums, err
+	}
+	defer r.Close()
+
+	for r.Next() {
+		var id, checksum string
+		if err := r.Scan(&id, &checksum); err != nil {
+			return checksums, err
+		}
+
+		checksums[id] = checksum
+	}
+
+	return checksums, r.Err()
+}
+
+// RecordSource - Removes the synsets a file no longer contains and records its new hash,
+// in a single transaction.
+//
+// Words left without any synset are removed as well.
+func (repo *DictionaryRepository) RecordSource(ctx context.Context, update types.SourceUpdate) error {
+	return repo.transaction(ctx, func(t *sql.Tx) error {
+		if len(update.Stale) > 0 {
+			var q = dbsql.NewQuery(repo.dialect)
+
+			/* A synset that moved to another file belongs to that file now */
+			var stale = fmt.Sprintf(
+				`SELECT id FROM synsets WHERE source = %s AND id IN (%s)`,
+				q.Bind(update.Source.Name),
+				q.BindAll(helpers.Map(update.Stale, func(_ int, id string) any { return id })...),
+			)
+
+			queries := []string{
+				`DELETE FROM relations WHERE source_id IN (%s)`,
+				`DELETE FROM synsets WHERE id IN (%s)`,
+			}
+
+			for _, query := range queries {
+				if _, err := t.ExecContext(ctx, fmt.Sprintf(query, stale), q.Args()...); err != nil {
+					return err
+				}
+			}
+		}
+
+		var known bool
+		var p = repo.dialect.Placeholder
+
+		if err := t.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM sources WHERE name = `+p(1)+`)`, update.Source.Name).Scan(&known); err != nil {
+			return err
+		}
+
+		/* Only a file ingested before may have dropped members from its synsets */
+		if known {
+			query := `DELETE FROM words WHERE NOT EXISTS (SELECT 1 FROM associations a WHERE a.word_id = words.id)`
+			if _, err := t.ExecContext(ctx, query); err != nil {
+				return err
+			}
+		}
+
+		_, err := t.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO sources(name, hash, synsets, imported_at)
+			VALUES(%s, %s, %s, CURRENT_TIMESTAMP)
+			%s
+		`, p(1), p(2), p(3), repo.dialect.Upsert([]string{"name"}, "hash", "synsets", "imported_at")),
+			update.Source.Name, update.Source.Hash, update.Source.Synsets)
+
+		return err
+	})
+}
@@ -27,8 +27,13 @@ func ConnectDatabase(options protocols.DBConnectOptions) (protocols.SqlBackend,
 		}
 
 		return db, nil
-	case protocols.SQLite3Adapter:
-		db, err := UseSQLite(options.Filename)
+	case protocols.SQLite3Adapter, protocols.PureSQLiteAdapter:
+		var open, dsn = UseSQLite, sqliteDSN
+		if options.Adapter == protocols.PureSQLiteAdapter {
+			open, dsn = UsePureSQLite, pureSQLiteDSN
+		}
+
+		db, err := open(options.Filename)
 		if err != nil {
 			return nil, err
 		}
@@ -39,7 +44,8 @@ func ConnectDatabase(options protocols.DBConnectOptions) (protocols.SqlBackend,
 
 		if options.VerboseLogging {
 			loggerAdapter := zerologadapter.New(zerolog.New(os.Stdout))
-			db = sqldblogger.OpenDriver(options.Filename, db.Driver(), loggerAdapter)
+			/* The logger opens connections of its own, which need the options of the DSN */
+			db = sqldblogger.OpenDriver(dsn(options.Filename), db.Driver(), loggerAdapter)
 
 			if db == nil {
 				return db, fmt.Errorf("database logger failed")
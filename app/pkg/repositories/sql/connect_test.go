@@ -0,0 +1,53 @@
+package dbsql_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	dbsql "github.com/oleoneto/redic/app/pkg/repositories/sql"
+)
+
+func Test_ConnectDatabase(t *testing.T) {
+	tests := []struct {
+		adapter protocols.SQLAdapter
+		verbose bool
+	}{
+		{adapter: protocols.SQLite3Adapter},
+		{adapter: protocols.SQLite3Adapter, verbose: true},
+		{adapter: protocols.PureSQLiteAdapter},
+		{adapter: protocols.PureSQLiteAdapter, verbose: true},
+	}
+
+	for _, tt := range tests {
+		name := string(tt.adapter)
+		if tt.verbose {
+			name += "/verbose"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			db, err := dbsql.ConnectDatabase(protocols.DBConnectOptions{
+				Adapter:        tt.adapter,
+				Filename:       filepath.Join(t.TempDir(), "redic.db"),
+				VerboseLogging: tt.verbose,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.(io.Closer).Close()
+
+			for pragma, want := range map[string]int{"foreign_keys": 1, "busy_timeout": 5000} {
+				var got int
+				if err := db.QueryRowContext(context.Background(), `PRAGMA `+pragma).Scan(&got); err != nil {
+					t.Fatal(err)
+				}
+
+				if got != want {
+					t.Errorf("PRAGMA %s = %d, want %d", pragma, got, want)
+				}
+			}
+		})
+	}
+}
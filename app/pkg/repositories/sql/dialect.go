@@ -0,0 +1,133 @@
+package dbsql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/search"
+)
+
+// Dialect - the SQL that sets one adapter apart from another.
+//
+// Queries are written once against a dialect, so every repository method works alike on
+// every engine. Usage:
+//
+//	d := DialectOf(protocols.PostgreSQLAdapter)
+//	d.Placeholder(2)                 // $2
+//	d.Upsert([]string{"id"}, "text") // ON CONFLICT (id) DO UPDATE SET text = excluded.text
+//	d.FullText("$1", w).Match        // a.document @@ to_tsquery('simple', $1)
+type Dialect interface {
+	Adapter() protocols.SQLAdapter
+
+	// Placeholder - the n-th query argument, counting from 1 (i.e $1, ?1).
+	Placeholder(n int) string
+
+	// Upsert - the conflict clause of an INSERT that updates the given columns
+	// when a row with the same keys exists, or leaves it as is when no column is given.
+	Upsert(keys []string, columns ...string) string
+
+	// Returning - the clause of a statement that yields the given columns of the rows it wrote.
+	Returning(columns ...string) string
+
+	// FullText - the parts of a search for synset members by their word, definition and examples,
+	// given the placeholder its terms are bound to and the weights of matches in each.
+	FullText(terms string, weights types.Ranking) FullText
+
+	// SearchTerms - a parsed query in the syntax of the engine, as the dialect binds it for FullText.
+	SearchTerms(query search.Expr) string
+}
+
+// FullText - the parts of a full-text search. From joins the associations (a), words (w),
+// synsets (s) and explanations (e) of every searchable synset member, which Match filters.
+// Ranks order ascending, best first, on a scale of the engine's own.
+type FullText struct {
+	From  string
+	Match string
+	Rank  string
+}
+
+// DialectOf - returns the dialect of the adapter, SQLite's when it is not known.
+func DialectOf(adapter protocols.SQLAdapter) Dialect {
+	if adapter == protocols.PostgreSQLAdapter {
+		return postgres{}
+	}
+
+	return sqlite{}
+}
+
+type sqlite struct{}
+
+func (sqlite) Adapter() protocols.SQLAdapter { return protocols.SQLite3Adapter }
+
+func (sqlite) Placeholder(n int) string { return fmt.Sprintf("?%d", n) }
+
+func (sqlite) Upsert(keys []string, columns ...string) string { return upsert(keys, columns) }
+
+func (sqlite) Returning(columns ...string) string {
+	return "RETURNING " + strings.Join(columns, ", ")
+}
+
+func (sqlite) FullText(terms string, weights types.Ranking) FullText {
+	return FullText{
+		From: `
+			redic_
+			JOIN associations a ON a.id = redic_.rowid
+			JOIN words w ON w.id = a.word_id
+			JOIN synsets s ON s.id = a.synset_id
+			JOIN explanations e ON e.id = s.explanation_id`,
+		Match: fmt.Sprintf(`redic_ MATCH %s`, terms),
+		Rank:  fmt.Sprintf(`bm25(redic_, %g, %g, %g)`, weights.Word, weights.Definition, weights.Examples),
+	}
+}
+
+func (sqlite) SearchTerms(query search.Expr) string { return search.FTS5(query) }
+
+type postgres struct{}
+
+func (postgres) Adapter() protocols.SQLAdapter { return protocols.PostgreSQLAdapter }
+
+func (postgres) Placeholder(n int) string { return fmt.Sprintf("$%d", n) }
+
+func (postgres) Upsert(keys []string, columns ...string) string { return upsert(keys, columns) }
+
+func (postgres) Returning(columns ...string) string {
+	return "RETURNING " + strings.Join(columns, ", ")
+}
+
+// The simple configuration neither stems nor drops stop words, as FTS5's default tokenizer does not.
+// Documents label words A, definitions B and examples C, which ts_rank_cd weighs by an array
+// of {D, C, B, A} weights of at most 1, so weights are scaled down to the largest.
+func (postgres) FullText(terms string, weights types.Ranking) FullText {
+	query := fmt.Sprintf(`to_tsquery('simple', %s)`, terms)
+	scale := max(weights.Word, weights.Definition, weights.Examples, 1)
+
+	return FullText{
+		From: `
+			associations a
+			JOIN words w ON w.id = a.word_id
+			JOIN synsets s ON s.id = a.synset_id
+			JOIN explanations e ON e.id = s.explanation_id`,
+		Match: fmt.Sprintf(`a.document @@ %s`, query),
+		Rank:  fmt.Sprintf(`-ts_rank_cd('{0, %g, %g, %g}', a.document, %s)`, weights.Examples/scale, weights.Definition/scale, weights.Word/scale, query),
+	}
+}
+
+func (postgres) SearchTerms(query search.Expr) string { return search.TSQuery(query) }
+
+// upsert - both engines share PostgreSQL's syntax.
+func upsert(keys []string, columns []string) string {
+	clause := fmt.Sprintf("ON CONFLICT (%s)", strings.Join(keys, ", "))
+
+	if len(columns) == 0 {
+		return clause + " DO NOTHING"
+	}
+
+	var updates []string
+	for _, column := range columns {
+		updates = append(updates, fmt.Sprintf("%[1]s = excluded.%[1]s", column))
+	}
+
+	return clause + " DO UPDATE SET " + strings.Join(updates, ", ")
+}
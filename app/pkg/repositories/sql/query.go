@@ -0,0 +1,66 @@
+package dbsql
+
+import (
+	"strings"
+)
+
+// Query - numbers the arguments of a statement in the order they are bound, in the
+// placeholder style of its dialect. Usage:
+//
+//	q := NewQuery(dialect)
+//	var where Conditions
+//	where.Add(`word = ` + q.Bind("dog"))
+//	where.Add(`synset_id IN (` + q.BindAll(ids...) + `)`)
+//	db.QueryContext(ctx, `SELECT * FROM dictionary `+where.Where(), q.Args()...)
+type Query struct {
+	Dialect
+	args []any
+}
+
+func NewQuery(dialect Dialect) *Query {
+	return &Query{Dialect: dialect, args: []any{}}
+}
+
+// Bind - adds an argument and returns its placeholder.
+func (q *Query) Bind(value any) string {
+	q.args = append(q.args, value)
+	return q.Placeholder(len(q.args))
+}
+
+// BindAll - adds every argument and returns their placeholders, separated by commas.
+func (q *Query) BindAll(values ...any) string {
+	var placeholders = make([]string, len(values))
+	for i, value := range values {
+		placeholders[i] = q.Bind(value)
+	}
+
+	return strings.Join(placeholders, ", ")
+}
+
+// Args - the arguments bound so far, in order.
+func (q *Query) Args() []any { return q.args }
+
+// Conditions - filters that must all hold.
+type Conditions []string
+
+func (c *Conditions) Add(condition string) {
+	*c = append(*c, condition)
+}
+
+// Where - the conditions as a WHERE clause, or nothing if there are none.
+func (c Conditions) Where() string {
+	if len(c) == 0 {
+		return ""
+	}
+
+	return "WHERE " + strings.Join(c, " AND ")
+}
+
+// And - the conditions as a continuation of an existing WHERE clause, or nothing if there are none.
+func (c Conditions) And() string {
+	if len(c) == 0 {
+		return ""
+	}
+
+	return "AND " + strings.Join(c, " AND ")
+}
@@ -0,0 +1,60 @@
+package dbsql_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/oleoneto/redic/app/domain/protocols"
+	dbsql "github.com/oleoneto/redic/app/pkg/repositories/sql"
+)
+
+func Test_Query(t *testing.T) {
+	tests := []struct {
+		adapter protocols.SQLAdapter
+		want    string
+		upsert  string
+	}{
+		{
+			adapter: protocols.SQLite3Adapter,
+			want:    "WHERE word = ?1 AND part_of_speech = ?2 AND synset_id IN (?3, ?4)",
+			upsert:  "ON CONFLICT (word_id, synset_id) DO UPDATE SET position = excluded.position, explicit = excluded.explicit",
+		},
+		{
+			adapter: protocols.PostgreSQLAdapter,
+			want:    "WHERE word = $1 AND part_of_speech = $2 AND synset_id IN ($3, $4)",
+			upsert:  "ON CONFLICT (word_id, synset_id) DO UPDATE SET position = excluded.position, explicit = excluded.explicit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.adapter), func(t *testing.T) {
+			q := dbsql.NewQuery(dbsql.DialectOf(tt.adapter))
+
+			var where dbsql.Conditions
+			where.Add(`word = ` + q.Bind("dog"))
+			where.Add(`part_of_speech = ` + q.Bind("n"))
+			where.Add(`synset_id IN (` + q.BindAll("02084071-n", "02083346-n") + `)`)
+
+			if got := where.Where(); got != tt.want {
+				t.Errorf("Where() = %q, want %q", got, tt.want)
+			}
+
+			if got, want := q.Args(), []any{"dog", "n", "02084071-n", "02083346-n"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("Args() = %v, want %v", got, want)
+			}
+
+			if got := q.Upsert([]string{"word_id", "synset_id"}, "position", "explicit"); got != tt.upsert {
+				t.Errorf("Upsert() = %q, want %q", got, tt.upsert)
+			}
+
+			if got := q.Upsert([]string{"source_id"}); got != "ON CONFLICT (source_id) DO NOTHING" {
+				t.Errorf("Upsert() = %q, want a DO NOTHING clause", got)
+			}
+		})
+	}
+
+	var none dbsql.Conditions
+	if none.Where() != "" || none.And() != "" {
+		t.Errorf("Where() and And() of no conditions = %q, %q, want empty", none.Where(), none.And())
+	}
+}
@@ -2,12 +2,15 @@ package dbsql
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/mattn/go-sqlite3"
+	puresqlite "modernc.org/sqlite"
 )
 
 func UsePG(dsn string) (*sql.DB, error) {
@@ -19,28 +22,67 @@ func UsePG(dsn string) (*sql.DB, error) {
 	return d, nil
 }
 
+var registerDriver sync.Once
+
 func UseSQLite(dbname string) (*sql.DB, error) {
 	if dbname == "" {
 		return nil, fmt.Errorf("no database name provided")
 	}
 
-	var regex = func(re, s string) (bool, error) { return regexp.MatchString(re, s) }
+	registerDriver.Do(func() {
+		var regex = func(re, s string) (bool, error) { return regexp.MatchString(re, s) }
 
-	sql.Register("sqlite3_ext",
-		&sqlite3.SQLiteDriver{
-			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
-				return conn.RegisterFunc("regexp", regex, true)
+		sql.Register("sqlite3_ext",
+			&sqlite3.SQLiteDriver{
+				ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+					return conn.RegisterFunc("regexp", regex, true)
+				},
 			},
-		},
-	)
+		)
+	})
 
-	options := []string{"fts5=on", "_cslike=off", "_fk=on", "_ignore_check_constraints=off", "_journal=WAL"}
-	d, err := sql.Open("sqlite3_ext", fmt.Sprintf("%s?%s", dbname, strings.Join(options, "&")))
+	d, err := sql.Open("sqlite3_ext", sqliteDSN(dbname))
 	if err != nil {
 		return nil, err
 	}
 
-	d.Exec(`PRAGMA busy_timeout = 5000`)
+	return d, nil
+}
+
+// sqliteDSN - the database with the options every connection of the CGO driver is opened with.
+func sqliteDSN(dbname string) string {
+	options := []string{"fts5=on", "_cslike=off", "_fk=on", "_ignore_check_constraints=off", "_journal=WAL", "_busy_timeout=5000"}
+	return fmt.Sprintf("%s?%s", dbname, strings.Join(options, "&"))
+}
+
+var registerRegexp sync.Once
+
+// UsePureSQLite - opens the database with a SQLite driver written in Go, which needs no CGO
+// and ships with FTS5 enabled, so that binaries can be built statically.
+func UsePureSQLite(dbname string) (*sql.DB, error) {
+	if dbname == "" {
+		return nil, fmt.Errorf("no database name provided")
+	}
+
+	registerRegexp.Do(func() {
+		puresqlite.MustRegisterDeterministicScalarFunction("regexp", 2, func(_ *puresqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			re, _ := args[0].(string)
+			s, _ := args[1].(string)
+
+			return regexp.MatchString(re, s)
+		})
+	})
+
+	d, err := sql.Open("sqlite", pureSQLiteDSN(dbname))
+	if err != nil {
+		return nil, err
+	}
 
 	return d, nil
 }
+
+// pureSQLiteDSN - the database with the pragmas every connection of the pure Go driver runs.
+func pureSQLiteDSN(dbname string) string {
+	options := []string{"_pragma=foreign_keys(1)", "_pragma=journal_mode(WAL)", "_pragma=busy_timeout(5000)"}
+	return fmt.Sprintf("%s?%s", dbname, strings.Join(options, "&"))
+}
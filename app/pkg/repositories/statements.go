@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	dbsql "github.com/oleoneto/redic/app/pkg/repositories/sql"
+	"github.com/sirupsen/logrus"
+)
+
+// statements - prepared once per transaction and reused for every row of a batch.
+type statements struct {
+	explanation  *sql.Stmt
+	synset       *sql.Stmt
+	word         *sql.Stmt
+	associations *sql.Stmt
+	association  *sql.Stmt
+	examples     *sql.Stmt
+	example      *sql.Stmt
+	relations    *sql.Stmt
+	relation     *sql.Stmt
+}
+
+func prepareStatements(ctx context.Context, t *sql.Tx, d dbsql.Dialect) (*statements, error) {
+	var s statements
+	var err error
+
+	prepare := func(stmt **sql.Stmt, query string) {
+		if err != nil {
+			return
+		}
+		*stmt, err = t.PrepareContext(ctx, query)
+	}
+
+	p := d.Placeholder
+
+	/* Creates a new explanation if one does not yet exist; updating it on conflict yields its id */
+	prepare(&s.explanation, fmt.Sprintf(`
+	INSERT INTO explanations(text)
+		VALUES(%s)
+		%s
+	%s
+	`, p(1), d.Upsert([]string{"text"}, "text"), d.Returning("id")))
+
+	/* Creates or refreshes the synset */
+	prepare(&s.synset, fmt.Sprintf(`
+	INSERT INTO synsets(id, ili, part_of_speech, explanation_id, language, source, checksum, lexfile)
+		VALUES(%s, NULLIF(%s, ''), %s, %s, %s, NULLIF(%s, ''), %s, NULLIF(%s, ''))
+		%s
+	`, p(1), p(2), p(3), p(4), p(5), p(6), p(7), p(8),
+		d.Upsert([]string{"id"}, "ili", "part_of_speech", "explanation_id", "language", "source", "checksum", "lexfile")))
+
+	/* Creates a new word entry if one does not yet exist */
+	prepare(&s.word, fmt.Sprintf(`
+	INSERT INTO words(text, part_of_speech, language)
+		VALUES(%s, %s, %s)
+		%s
+	%s
+	`, p(1), p(2), p(3), d.Upsert([]string{"text", "part_of_speech", "language"}, "text", "part_of_speech"), d.Returning("id")))
+
+	/* Members, examples and outgoing relations are replaced wholesale whenever their synset is written */
+	prepare(&s.associations, fmt.Sprintf(`DELETE FROM associations WHERE synset_id = %s AND explicit = %s`, p(1), p(2)))
+
+	/* Links the word to the synset as its n-th member */
+	prepare(&s.association, fmt.Sprintf(`
+	INSERT INTO associations(word_id, synset_id, position, explicit)
+		VALUES(%s, %s, %s, %s)
+		%s
+	`, p(1), p(2), p(3), p(4), d.Upsert([]string{"word_id", "synset_id"}, "position", "explicit")))
+
+	prepare(&s.examples, fmt.Sprintf(`DELETE FROM examples WHERE synset_id = %s`, p(1)))
+
+	prepare(&s.example, fmt.Sprintf(`
+	INSERT INTO examples(synset_id, position, text, source)
+		VALUES(%s, %s, %s, NULLIF(%s, ''))
+	`, p(1), p(2), p(3), p(4)))
+
+	prepare(&s.relations, fmt.Sprintf(`DELETE FROM relations WHERE source_id = %s`, p(1)))
+
+	prepare(&s.relation, fmt.Sprintf(`
+	INSERT INTO relations(source_id, target_id, relation_type)
+		VALUES(%s, %s, %s)
+		%s
+	`, p(1), p(2), p(3), d.Upsert([]string{"source_id", "target_id", "relation_type"})))
+
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (s *statements) Close() error {
+	var errs []error
+
+	for _, stmt := range []*sql.Stmt{s.explanation, s.synset, s.word, s.associations, s.association, s.examples, s.example, s.relations, s.relation} {
+		if stmt != nil {
+			errs = append(errs, stmt.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *statements) writeSynset(ctx context.Context, item types.NewSynsetInput) error {
+	var explanationId int64
+
+	if err := s.explanation.QueryRowContext(ctx, item.Definition()).Scan(&explanationId); err != nil {
+		logrus.Errorln("failed to add explanation", item.Definition())
+		return err
+	}
+
+	if _, err := s.synset.ExecContext(ctx, item.Id, item.ILI, item.PartOfSpeech, explanationId, types.LexiconLanguage(item.Language), item.Source, item.Checksum(), item.Lexfile); err != nil {
+		logrus.Errorln("failed to add synset", item.Id)
+		return err
+	}
+
+	if _, err := s.associations.ExecContext(ctx, item.Id, item.Explicit); err != nil {
+		logrus.Errorln("failed to clear members of", item.Id)
+		return err
+	}
+
+	for position, member := range item.Members {
+		var wordId int64
+
+		if err := s.word.QueryRowContext(ctx, member, item.PartOfSpeech, types.LexiconLanguage(item.Language)).Scan(&wordId); err != nil {
+			logrus.Errorln("failed to add word", member, "part_of_speech", item.PartOfSpeech)
+			return err
+		}
+
+		if _, err := s.association.ExecContext(ctx, wordId, item.Id, position, item.Explicit); err != nil {
+			logrus.Errorln("failed to associate word and synset for", member, wordId, item.Id)
+			return err
+		}
+	}
+
+	if _, err := s.examples.ExecContext(ctx, item.Id); err != nil {
+		logrus.Errorln("failed to clear examples for", item.Id)
+		return err
+	}
+
+	for position, example := range item.Examples {
+		if _, err := s.example.ExecContext(ctx, item.Id, position, example.Text, example.Source); err != nil {
+			logrus.Errorln("failed to add example for", item.Id, example.Text)
+			return err
+		}
+	}
+
+	if _, err := s.relations.ExecContext(ctx, item.Id); err != nil {
+		logrus.Errorln("failed to clear relations of", item.Id)
+		return err
+	}
+
+	for _, relation := range item.Relations {
+		if err := s.writeRelation(ctx, relation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *statements) writeRelation(ctx context.Context, item types.Relation) error {
+	if _, err := s.relation.ExecContext(ctx, item.Source, item.Target, item.Type); err != nil {
+		logrus.Errorln("failed to add relation", item.Type, "from", item.Source, "to", item.Target)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,375 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/ranking"
+)
+
+type kind int
+
+const (
+	end kind = iota
+	word
+	quoted // "a phrase", with "" for a quote within it
+	open
+	closing
+	or
+	near // NEAR(
+	comma
+	star
+	plus
+	minus
+)
+
+// punctuation - the tokens of a single character.
+var punctuation = map[rune]kind{'(': open, ')': closing, ',': comma, '*': star, '+': plus, '-': minus}
+
+// token - a part of a query, and its bytes in the query.
+type token struct {
+	kind       kind
+	text       string
+	start, end int
+}
+
+// Parse - the expression a query describes. Queries that are malformed, or that search for
+// nothing (i.e only excluded terms, or only punctuation), are a *types.QueryError.
+func Parse(query string) (Expr, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var p = parser{query: query, tokens: tokens}
+
+	e, err := p.all()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind == closing {
+		return nil, p.fail(t.start, "unexpected ) without a ( before it")
+	}
+
+	if e == nil {
+		return nil, p.fail(0, "nothing to search for")
+	}
+
+	return e, nil
+}
+
+// lex - splits a query into tokens. A + or - only marks a term at the start of one (i.e -wolf),
+// and is otherwise part of a word (i.e long-haired).
+func lex(query string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(query); {
+		r, size := utf8.DecodeRuneInString(query[i:])
+
+		/* Signs start terms, so they follow nothing but spaces and groups */
+		starts := i == 0 || strings.ContainsAny(query[i-1:i], " \t\n\r(")
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case strings.ContainsRune("(),*", r) || strings.ContainsRune("+-", r) && starts && i+1 < len(query) && !strings.ContainsAny(query[i+1:i+2], " \t\n\r),*"):
+			tokens = append(tokens, token{kind: punctuation[r], start: i, end: i + 1})
+			i++
+		case r == '"':
+			var b strings.Builder
+			var j = i + 1
+
+			for {
+				k := strings.IndexByte(query[j:], '"')
+				if k < 0 {
+					return nil, &types.QueryError{Query: query, Position: i, Message: "phrase is missing its closing quote"}
+				}
+
+				b.WriteString(query[j : j+k])
+				j += k + 1
+
+				/* "" is a quote within the phrase */
+				if j < len(query) && query[j] == '"' {
+					b.WriteByte('"')
+					j++
+					continue
+				}
+
+				break
+			}
+
+			tokens = append(tokens, token{kind: quoted, text: b.String(), start: i, end: j})
+			i = j
+		default:
+			j := i
+			for j < len(query) {
+				r, size := utf8.DecodeRuneInString(query[j:])
+				if unicode.IsSpace(r) || strings.ContainsRune(`()",*`, r) {
+					break
+				}
+				j += size
+			}
+
+			t := token{kind: word, text: query[i:j], start: i, end: j}
+
+			switch {
+			case t.text == "OR":
+				t.kind = or
+			case t.text == "NEAR" && j < len(query) && query[j] == '(':
+				t.kind, t.end = near, j+1
+			}
+
+			tokens = append(tokens, t)
+			i = t.end
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	query  string
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	if p.i == len(p.tokens) {
+		return token{kind: end, start: len(p.query), end: len(p.query)}
+	}
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if p.i < len(p.tokens) {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) fail(position int, message string) error {
+	return &types.QueryError{Query: p.query, Position: position, Message: message}
+}
+
+// all - terms up to the end of the query or group, every one of them required unless excluded.
+// Returns nil when they search for nothing.
+func (p *parser) all() (Expr, error) {
+	var e All
+	var excluded = -1 // where the first excluded term is
+
+	for {
+		switch t := p.peek(); t.kind {
+		case end, closing:
+			switch {
+			case len(e.Required) == 0 && len(e.Excluded) != 0:
+				return nil, p.fail(excluded, "nothing to search for but excluded terms")
+			case len(e.Required) == 0:
+				return nil, nil
+			case len(e.Required) == 1 && len(e.Excluded) == 0:
+				return e.Required[0], nil
+			}
+
+			return e, nil
+		case comma:
+			p.next()
+		default:
+			term, sign, err := p.any()
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case term == nil:
+			case sign.kind == minus:
+				if excluded < 0 {
+					excluded = sign.start
+				}
+				e.Excluded = append(e.Excluded, term)
+			default:
+				e.Required = append(e.Required, term)
+			}
+		}
+	}
+}
+
+// any - a term, or alternatives to one another joined by OR. Returns the sign of the term;
+// alternatives cannot be excluded one by one.
+func (p *parser) any() (Expr, token, error) {
+	if t := p.peek(); t.kind == or {
+		return nil, t, p.fail(t.start, "OR needs a term on each side")
+	}
+
+	first, sign, err := p.signed()
+	if err != nil || p.peek().kind != or {
+		return first, sign, err
+	}
+
+	var e Any
+	var signs = []token{sign}
+
+	if first != nil {
+		e.Alternatives = append(e.Alternatives, first)
+	}
+
+	for p.peek().kind == or {
+		operator := p.next()
+
+		switch p.peek().kind {
+		case end, closing, or, comma:
+			return nil, sign, p.fail(operator.start, "OR needs a term on each side")
+		}
+
+		alternative, sign, err := p.signed()
+		if err != nil {
+			return nil, sign, err
+		}
+
+		if alternative != nil {
+			e.Alternatives, signs = append(e.Alternatives, alternative), append(signs, sign)
+		}
+	}
+
+	for _, sign := range signs {
+		if sign.kind == minus {
+			return nil, sign, p.fail(sign.start, "excluded terms cannot be alternatives; exclude the group instead, i.e -(dog OR wolf)")
+		}
+	}
+
+	switch len(e.Alternatives) {
+	case 0:
+		return nil, sign, nil
+	case 1:
+		return e.Alternatives[0], sign, nil
+	}
+
+	return e, sign, nil
+}
+
+// signed - a term, after its sign if it has one.
+func (p *parser) signed() (Expr, token, error) {
+	var sign token
+	if t := p.peek(); t.kind == plus || t.kind == minus {
+		sign = p.next()
+	}
+
+	e, err := p.term()
+	return e, sign, err
+}
+
+// term - a phrase, group or NEAR group. Returns nil for words without any letters or digits.
+func (p *parser) term() (Expr, error) {
+	switch t := p.next(); t.kind {
+	case open:
+		e, err := p.all()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek().kind != closing {
+			return nil, p.fail(t.start, "( is missing its closing )")
+		}
+		p.next()
+
+		if e == nil {
+			return nil, p.fail(t.start, "group has nothing to search for")
+		}
+
+		return e, p.unexpectedStar()
+	case near:
+		return p.near(t)
+	case word, quoted:
+		phrase, err := p.phrase(t)
+		if err != nil || phrase == nil {
+			return nil, err
+		}
+		return *phrase, nil
+	case star:
+		return nil, p.fail(t.start, "* must directly follow a term, i.e tele*")
+	case closing:
+		return nil, p.fail(t.start, "unexpected ) without a ( before it")
+	default:
+		return nil, p.fail(t.start, "expected a term")
+	}
+}
+
+// phrase - the words of a word or quoted token, a prefix when a * directly follows it.
+// Returns nil for words without any letters or digits; quoted phrases must have some.
+func (p *parser) phrase(t token) (*Phrase, error) {
+	var phrase = Phrase{Quoted: t.kind == quoted}
+
+	for _, w := range ranking.Tokens(t.text) {
+		phrase.Words = append(phrase.Words, strings.ToLower(t.text[w.Start:w.End]))
+	}
+
+	if next := p.peek(); next.kind == star && next.start == t.end {
+		p.next()
+		phrase.Prefix = true
+	}
+
+	switch {
+	case len(phrase.Words) != 0:
+		return &phrase, p.unexpectedStar()
+	case phrase.Quoted:
+		return nil, p.fail(t.start, "phrase has nothing to search for")
+	case phrase.Prefix:
+		return nil, p.fail(t.end, "* must directly follow a term, i.e tele*")
+	}
+
+	return nil, nil
+}
+
+// near - the phrases of a NEAR( group, up to its closing parenthesis, and the distance
+// between them when it is given after a comma.
+func (p *parser) near(start token) (Expr, error) {
+	var e = Near{Distance: Distance}
+
+	for {
+		switch t := p.next(); t.kind {
+		case word, quoted:
+			phrase, err := p.phrase(t)
+			if err != nil {
+				return nil, err
+			}
+
+			if phrase != nil {
+				e.Phrases = append(e.Phrases, *phrase)
+			}
+		case comma:
+			distance := p.next()
+
+			n, err := strconv.Atoi(distance.text)
+			if distance.kind != word || err != nil || n < 0 {
+				return nil, p.fail(distance.start, "NEAR distance must be a whole number, i.e NEAR(dog bark, 5)")
+			}
+
+			e.Distance = n
+
+			if p.peek().kind != closing {
+				return nil, p.fail(p.peek().start, "NEAR( must end after its distance")
+			}
+		case closing:
+			if len(e.Phrases) < 2 {
+				return nil, p.fail(start.start, "NEAR( needs at least two terms or phrases")
+			}
+
+			return e, p.unexpectedStar()
+		case end:
+			return nil, p.fail(start.start, "NEAR( is missing its closing )")
+		default:
+			return nil, p.fail(t.start, "NEAR( only takes terms and phrases, then a distance")
+		}
+	}
+}
+
+// unexpectedStar - fails when a * follows what was just parsed, as it can only mark a prefix.
+func (p *parser) unexpectedStar() error {
+	if t := p.peek(); t.kind == star {
+		return p.fail(t.start, "* must directly follow a term, i.e tele*")
+	}
+	return nil
+}
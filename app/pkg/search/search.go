@@ -0,0 +1,234 @@
+// Package search parses the descriptions words are searched by, and compiles them into the
+// full-text queries of each backend. Descriptions are never handed to an engine as they are,
+// so quotes, hyphens, colons and keywords of the engine's own syntax mean nothing to it.
+//
+// The syntax:
+//
+//	small long tail        every term, in the word, its definition or its examples
+//	"sea lion"             terms that follow one another
+//	tele*, "sea li"*       terms that start with a prefix
+//	+dog                   a required term, as every term is unless excluded
+//	-wolf, -"sea lion"     an excluded term or phrase
+//	dog OR wolf            either term; OR binds tighter than the terms around it
+//	(big dog) OR wolf      a group
+//	NEAR(dog bark, 5)      phrases at most 5 terms apart (10 when left out)
+//
+// Terms are split the way text is indexed, on anything other than letters and digits,
+// so `long-haired` is the phrase "long haired". Only OR and NEAR( are keywords.
+//
+// Usage:
+//
+//	query, err := search.Parse(`barking -"sea lion" (dog OR wolf)`)
+//	search.FTS5(query)    // ("barking" AND ("dog" OR "wolf")) NOT "sea lion"
+//	search.TSQuery(query) // 'barking' & ( 'dog' | 'wolf' ) & ! ( 'sea' <-> 'lion' )
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oleoneto/redic/app/pkg/ranking"
+)
+
+// Distance - how many terms may separate the phrases of a NEAR group, unless it says otherwise.
+const Distance = 10
+
+// Expr - a parsed query: a Phrase, All, Any or Near.
+type Expr interface{ expr() }
+
+type (
+	// Phrase - words that follow one another, lowercase as they were written.
+	// The last word is a prefix when Prefix is set. Quoted phrases were written in quotes.
+	Phrase struct {
+		Words  []string
+		Prefix bool
+		Quoted bool
+	}
+
+	// All - every required expression, and none of the excluded ones.
+	All struct {
+		Required []Expr
+		Excluded []Expr
+	}
+
+	// Any - any of the alternatives.
+	Any struct {
+		Alternatives []Expr
+	}
+
+	// Near - every phrase, at most Distance terms apart from one another.
+	Near struct {
+		Phrases  []Phrase
+		Distance int
+	}
+)
+
+func (Phrase) expr() {}
+func (All) expr()    {}
+func (Any) expr()    {}
+func (Near) expr()   {}
+
+// FTS5 - the query in SQLite's FTS5 syntax, with every phrase quoted.
+func FTS5(e Expr) string {
+	switch e := e.(type) {
+	case Phrase:
+		return fts5Phrase(e)
+	case Any:
+		return "(" + join(e.Alternatives, " OR ", FTS5) + ")"
+	case Near:
+		var phrases = make([]string, len(e.Phrases))
+		for i, p := range e.Phrases {
+			phrases[i] = fts5Phrase(p)
+		}
+
+		return fmt.Sprintf("NEAR(%s, %d)", strings.Join(phrases, " "), e.Distance)
+	case All:
+		/* NOT is binary in FTS5, and excludes its right side from its left */
+		query := join(e.Required, " AND ", FTS5)
+		if len(e.Required) > 1 && len(e.Excluded) > 0 {
+			query = "(" + query + ")"
+		}
+
+		for _, excluded := range e.Excluded {
+			query += " NOT " + fts5Excluded(excluded)
+		}
+
+		return query
+	}
+
+	return ""
+}
+
+// fts5Excluded - the expression as the right side of NOT, which binds tighter than AND,
+// in parentheses unless it is a single phrase, a NEAR group or an Any, which is in parentheses already.
+func fts5Excluded(e Expr) string {
+	if e, ok := e.(All); ok && len(e.Required)+len(e.Excluded) > 1 {
+		return "(" + FTS5(e) + ")"
+	}
+
+	return FTS5(e)
+}
+
+func fts5Phrase(p Phrase) string {
+	phrase := `"` + strings.Join(p.Words, " ") + `"`
+	if p.Prefix {
+		phrase += "*"
+	}
+	return phrase
+}
+
+// TSQuery - the query in PostgreSQL's to_tsquery syntax.
+//
+// tsquery has no operator for terms within a distance of one another, so the phrases
+// of a NEAR group must only all occur, and ts_rank_cd ranks those closer together higher.
+func TSQuery(e Expr) string {
+	switch e := e.(type) {
+	case Phrase:
+		var lexemes = make([]string, len(e.Words))
+		for i, word := range e.Words {
+			lexemes[i] = "'" + word + "'"
+		}
+
+		if e.Prefix {
+			lexemes[len(lexemes)-1] += ":*"
+		}
+
+		return strings.Join(lexemes, " <-> ")
+	case Any:
+		return "( " + join(e.Alternatives, " | ", TSQuery) + " )"
+	case Near:
+		var phrases = make([]Expr, len(e.Phrases))
+		for i, p := range e.Phrases {
+			phrases[i] = p
+		}
+
+		return join(phrases, " & ", TSQuery)
+	case All:
+		query := join(e.Required, " & ", TSQuery)
+
+		for _, excluded := range e.Excluded {
+			query += " & ! " + negated(excluded)
+		}
+
+		return query
+	}
+
+	return ""
+}
+
+// negated - the expression as the operand of !, which binds tighter than any other operator,
+// in parentheses unless it is a single lexeme or an Any, which is in parentheses already.
+func negated(e Expr) string {
+	switch e := e.(type) {
+	case Phrase:
+		if len(e.Words) == 1 {
+			return TSQuery(e)
+		}
+	case Any:
+		return TSQuery(e)
+	}
+
+	return "( " + TSQuery(e) + " )"
+}
+
+func join(exprs []Expr, separator string, compile func(Expr) string) string {
+	var parts = make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = compile(e)
+	}
+	return strings.Join(parts, separator)
+}
+
+// Phrases - the phrases a query searches for, leaving out those it excludes, as the terms
+// they are indexed by (i.e Café → cafe). The last term of a prefix keeps its trailing *.
+func Phrases(e Expr) [][]string {
+	var phrases [][]string
+
+	var walk func(Expr)
+	walk = func(e Expr) {
+		switch e := e.(type) {
+		case Phrase:
+			if terms := e.Terms(); len(terms) != 0 {
+				phrases = append(phrases, terms)
+			}
+		case Any:
+			for _, alternative := range e.Alternatives {
+				walk(alternative)
+			}
+		case Near:
+			for _, p := range e.Phrases {
+				walk(p)
+			}
+		case All:
+			for _, required := range e.Required {
+				walk(required)
+			}
+		}
+	}
+
+	walk(e)
+
+	return phrases
+}
+
+// Terms - the terms of every phrase the query searches for.
+func Terms(e Expr) []string {
+	var terms []string
+
+	for _, phrase := range Phrases(e) {
+		terms = append(terms, phrase...)
+	}
+
+	return terms
+}
+
+// Terms - the words of the phrase as they are indexed. The last one keeps a trailing * when it is a prefix.
+func (p Phrase) Terms() []string {
+	var terms = ranking.Tokenize(strings.Join(p.Words, " "))
+
+	if p.Prefix && len(terms) != 0 {
+		terms[len(terms)-1] += "*"
+	}
+
+	return terms
+}
@@ -0,0 +1,152 @@
+package search_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/search"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		query   string
+		fts5    string
+		tsquery string
+	}{
+		{query: "small long tail", fts5: `"small" AND "long" AND "tail"`, tsquery: "'small' & 'long' & 'tail'"},
+		{query: "Small  LONG", fts5: `"small" AND "long"`, tsquery: "'small' & 'long'"},
+		{query: "dog OR wolf", fts5: `("dog" OR "wolf")`, tsquery: "( 'dog' | 'wolf' )"},
+		{query: "+dog -wolf", fts5: `"dog" NOT "wolf"`, tsquery: "'dog' & ! 'wolf'"},
+		{query: `"sea lion"`, fts5: `"sea lion"`, tsquery: "'sea' <-> 'lion'"},
+		{query: `"sea li"* seal`, fts5: `"sea li"* AND "seal"`, tsquery: "'sea' <-> 'li':* & 'seal'"},
+		{query: "tele*", fts5: `"tele"*`, tsquery: "'tele':*"},
+		{query: `barking (dogs OR "dog")`, fts5: `"barking" AND ("dogs" OR "dog")`, tsquery: "'barking' & ( 'dogs' | 'dog' )"},
+		{query: "barking dog OR wolf", fts5: `"barking" AND ("dog" OR "wolf")`, tsquery: "'barking' & ( 'dog' | 'wolf' )"},
+		{query: "(big dog) OR wolf", fts5: `("big" AND "dog" OR "wolf")`, tsquery: "( 'big' & 'dog' | 'wolf' )"},
+		{query: `barking -"sea lion" (dog OR wolf)`, fts5: `("barking" AND ("dog" OR "wolf")) NOT "sea lion"`, tsquery: "'barking' & ( 'dog' | 'wolf' ) & ! ( 'sea' <-> 'lion' )"},
+		{query: "dog -(wolf OR fox)", fts5: `"dog" NOT ("wolf" OR "fox")`, tsquery: "'dog' & ! ( 'wolf' | 'fox' )"},
+		{query: "cat -(big dog)", fts5: `"cat" NOT ("big" AND "dog")`, tsquery: "'cat' & ! ( 'big' & 'dog' )"},
+		{query: "cat -(big -dog)", fts5: `"cat" NOT ("big" NOT "dog")`, tsquery: "'cat' & ! ( 'big' & ! 'dog' )"},
+		{query: "NEAR(dog bark)", fts5: `NEAR("dog" "bark", 10)`, tsquery: "'dog' & 'bark'"},
+		{query: `NEAR(dog "sea lion", 3)`, fts5: `NEAR("dog" "sea lion", 3)`, tsquery: "'dog' & 'sea' <-> 'lion'"},
+		{query: "long-haired terrier", fts5: `"long haired" AND "terrier"`, tsquery: "'long' <-> 'haired' & 'terrier'"},
+		{query: `"it""s"`, fts5: `"it s"`, tsquery: "'it' <-> 's'"},
+		{query: "it's café", fts5: `"it s" AND "café"`, tsquery: "'it' <-> 's' & 'café'"},
+		{query: "dog NOT wolf AND fox", fts5: `"dog" AND "not" AND "wolf" AND "and" AND "fox"`, tsquery: "'dog' & 'not' & 'wolf' & 'and' & 'fox'"},
+		{query: `title:dog "x" & near`, fts5: `"title dog" AND "x" AND "near"`, tsquery: "'title' <-> 'dog' & 'x' & 'near'"},
+		{query: "dog, cat", fts5: `"dog" AND "cat"`, tsquery: "'dog' & 'cat'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			e, err := search.Parse(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := search.FTS5(e); got != tt.fts5 {
+				t.Errorf("FTS5(%q) = %s, want %s", tt.query, got, tt.fts5)
+			}
+
+			if got := search.TSQuery(e); got != tt.tsquery {
+				t.Errorf("TSQuery(%q) = %s, want %s", tt.query, got, tt.tsquery)
+			}
+		})
+	}
+}
+
+func Test_Parse_Errors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+	}{
+		{query: "", position: 0},
+		{query: "?!", position: 0},
+		{query: `dog "sea lion`, position: 4},
+		{query: `dog ""`, position: 4},
+		{query: "dog (wolf", position: 4},
+		{query: "dog wolf)", position: 8},
+		{query: "dog ()", position: 4},
+		{query: "dog OR", position: 4},
+		{query: "OR dog", position: 0},
+		{query: "dog OR OR wolf", position: 4},
+		{query: "-dog -wolf", position: 0},
+		{query: "dog OR -wolf", position: 7},
+		{query: "dog *", position: 4},
+		{query: "*dog", position: 0},
+		{query: "(dog)*", position: 5},
+		{query: "NEAR(dog)", position: 0},
+		{query: "NEAR(dog bark", position: 0},
+		{query: "NEAR(dog bark, far)", position: 15},
+		{query: "NEAR(dog (bark))", position: 9},
+		{query: "café (", position: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := search.Parse(tt.query)
+
+			var invalid *types.QueryError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Parse(%q) error = %v, want a *types.QueryError", tt.query, err)
+			}
+
+			if invalid.Position != tt.position || invalid.Query != tt.query {
+				t.Errorf("Parse(%q) error at %d of %q, want at %d", tt.query, invalid.Position, invalid.Query, tt.position)
+			}
+		})
+	}
+}
+
+func Test_Phrases(t *testing.T) {
+	tests := []struct {
+		query string
+		want  [][]string
+	}{
+		{query: "a young dog", want: [][]string{{"a"}, {"young"}, {"dog"}}},
+		{query: `"sea lion" OR seal*`, want: [][]string{{"sea", "lion"}, {"seal*"}}},
+		{query: `(dogs OR "dog") -wolf "AND"`, want: [][]string{{"dogs"}, {"dog"}, {"and"}}},
+		{query: `"the ""sea"" lion"*`, want: [][]string{{"the", "sea", "lion*"}}},
+		{query: "NEAR(Café lait)", want: [][]string{{"cafe"}, {"lait"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			e, err := search.Parse(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := search.Phrases(e); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Phrases(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Terms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "a young dog", want: []string{"a", "young", "dog"}},
+		{query: `"sea lion" OR seal*`, want: []string{"sea", "lion", "seal*"}},
+		{query: `barking (dogs OR "dog") -wolf`, want: []string{"barking", "dogs", "dog"}},
+		{query: "Café-au-lait", want: []string{"cafe", "au", "lait"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			e, err := search.Parse(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := search.Terms(e); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Terms(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,22 +1,65 @@
 package core
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/mitchellh/go-homedir"
 	"github.com/oleoneto/redic/app"
 	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/migrations"
+	"github.com/oleoneto/redic/app/pkg/morphy"
+	"github.com/oleoneto/redic/app/pkg/pagination"
+	"github.com/oleoneto/redic/app/pkg/repositories/memory"
 	dbsql "github.com/oleoneto/redic/app/pkg/repositories/sql"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// ConnectDatabase - connects to the database, refusing to run against an outdated schema.
 func (c *CommandState) ConnectDatabase(cmd *cobra.Command, args []string) {
+	c.OpenDatabase(cmd, args)
+
+	if c.Adapter == protocols.MemoryAdapter {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+
+	migrator, err := migrations.NewMigrator(c.Database, c.Adapter)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := migrator.Check(ctx); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// OpenDatabase - connects to the database whatever the state of its schema.
+// The memory adapter loads the embedded dictionary instead.
+func (c *CommandState) OpenDatabase(cmd *cobra.Command, args []string) {
+	c.Adapter = protocols.SQLAdapter(cmd.Flag("adapter").Value.String())
+
+	if c.Adapter == protocols.MemoryAdapter {
+		c.LoadDictionary(cmd, args)
+		return
+	}
+
 	dbpath := viper.Get("database.path")
 
 	db, err := dbsql.ConnectDatabase(protocols.DBConnectOptions{
-		Adapter:        protocols.SQLAdapter(cmd.Flag("adapter").Value.String()),
+		Adapter:        c.Adapter,
 		DSN:            *c.Flags.DatabaseURL,
 		Filename:       dbpath.(string),
 		VerboseLogging: c.Flags.VerboseLogging,
@@ -28,8 +71,181 @@ func (c *CommandState) ConnectDatabase(cmd *cobra.Command, args []string) {
 	c.Database = db
 
 	app.New(protocols.DBConnectOptions{
-		DB: c.Database,
+		DB:      c.Database,
+		Adapter: c.Adapter,
 	})
+
+	c.configureRanking()
+	c.configureCursors()
+	c.configureMorphology()
+}
+
+// LoadDictionary - reads the embedded dictionary into memory, in place of a database.
+func (c *CommandState) LoadDictionary(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Minute)
+	defer cancel()
+
+	dictionary, err := memory.Load(ctx, c.Files, "data/english")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	app.NewWithBackend(dictionary)
+	c.configureRanking()
+	c.configureCursors()
+	c.configureMorphology()
+}
+
+// configureRanking - ranks searches by the weights in the search.ranking section of the config
+// (i.e search.ranking.headword: 6). Weights left out keep their default.
+func (c *CommandState) configureRanking() {
+	if !viper.IsSet("search.ranking") {
+		return
+	}
+
+	ranking := types.DefaultRanking
+	if err := viper.UnmarshalKey("search.ranking", &ranking); err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := app.DictionaryController.SetRanking(ranking); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// configureCursors - signs search cursors with the search.cursor_secret of the config, so those
+// handed out by one command are still good in the next. Without one, the secret kept next to the config is used.
+func (c *CommandState) configureCursors() {
+	var key = viper.GetString("search.cursor_secret")
+	if key == "" {
+		key = cursorSecret()
+	}
+
+	secret, err := hex.DecodeString(key)
+	if err != nil {
+		log.Fatalln(fmt.Errorf("invalid search.cursor_secret: %w", err))
+	}
+
+	if err := app.DictionaryController.SetCursorSecret(secret); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// cursorSecret - the secret in ~/.redic/cursor_secret, made up and saved there the first time.
+// The config is left as the user wrote it. Without a directory to keep it in, cursors only last as long as the process.
+func cursorSecret() string {
+	var secret = hex.EncodeToString(pagination.Secret())
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return secret
+	}
+
+	dir := filepath.Join(home, cliDir)
+	path := filepath.Join(dir, "cursor_secret")
+
+	if saved, ok := savedSecret(path); ok {
+		return saved
+	}
+
+	/* The secret is written in full before it is linked into place, and whichever command links it first decides it */
+	f, err := os.CreateTemp(dir, "cursor_secret-*")
+	if err != nil {
+		return secret
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(secret + "\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return secret
+	}
+
+	if err := os.Link(f.Name(), path); err != nil {
+		if saved, ok := savedSecret(path); ok {
+			return saved
+		}
+	}
+
+	return secret
+}
+
+// savedSecret - the secret saved at the path, unless there is none or it is not one.
+func savedSecret(path string) (string, bool) {
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	key := strings.TrimSpace(string(saved))
+	if _, err := hex.DecodeString(key); err != nil || key == "" {
+		return "", false
+	}
+
+	return key, true
+}
+
+// configureMorphology - adds the exception lists kept in ~/.redic/exceptions, left there by imports
+// of WordNet releases, to those morphy lemmatizes searches with.
+func (c *CommandState) configureMorphology() {
+	home, err := homedir.Dir()
+	if err != nil {
+		return
+	}
+
+	dir := filepath.Join(home, cliDir, exceptionsDir)
+
+	lists, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, list := range lists {
+		pos, ok := morphy.ExceptionList(list.Name())
+		if !ok {
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(dir, list.Name()))
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		err = morphy.AddExceptions(pos, f)
+		f.Close()
+
+		if err != nil {
+			log.Fatalln(fmt.Errorf("%v: %w", list.Name(), err))
+		}
+	}
+}
+
+// KeepExceptionList - saves an exception list (i.e verb.exc) to ~/.redic/exceptions, replacing
+// the one of the same name, so that every later command lemmatizes with it.
+func (c *CommandState) KeepExceptionList(name string, r io.Reader) error {
+	home, err := homedir.Dir()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(home, cliDir, exceptionsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(dir, path.Base(name)))
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
 }
 
 func (c *CommandState) BeforeHook(cmd *cobra.Command, args []string) {
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/progress"
+	"github.com/oleoneto/redic/app/pkg/ingestion"
+	"golang.org/x/term"
+)
+
+// IngestionProgress renders a live progress bar for an ingestion pipeline on stderr.
+// When stderr is not a terminal (i.e redirected to a log), it writes a plain line of totals
+// every few seconds instead.
+type IngestionProgress struct {
+	writer  progress.Writer // nil when stderr is not a terminal
+	files   *progress.Tracker
+	synsets *progress.Tracker
+
+	mu       sync.Mutex
+	trackers map[string]*progress.Tracker
+
+	plain io.Writer
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+var _ ingestion.Progress = (*IngestionProgress)(nil)
+
+// plainFrequency - how often totals are written when stderr is not a terminal.
+const plainFrequency = 5 * time.Second
+
+func NewIngestionProgress(files int) *IngestionProgress {
+	p := &IngestionProgress{
+		files:    &progress.Tracker{Message: "files", Total: int64(files)},
+		synsets:  &progress.Tracker{Message: "synsets written"},
+		trackers: map[string]*progress.Tracker{},
+	}
+
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		p.plain, p.stop, p.done = os.Stderr, make(chan struct{}), make(chan struct{})
+		go p.report()
+		return p
+	}
+
+	w := progress.NewWriter()
+	w.SetOutputWriter(os.Stderr)
+	w.SetAutoStop(false)
+	w.SetTrackerLength(30)
+	w.SetMessageLength(30)
+	w.SetUpdateFrequency(100 * time.Millisecond)
+	w.SetStyle(progress.StyleDefault)
+	w.Style().Visibility.ETA = true
+	w.Style().Visibility.Value = true
+
+	p.writer = w
+
+	w.AppendTracker(p.files)
+	w.AppendTracker(p.synsets)
+
+	go w.Render()
+
+	return p
+}
+
+// report - writes the totals so far every few seconds, and once more when stopped.
+func (p *IngestionProgress) report() {
+	defer close(p.done)
+
+	var start = time.Now()
+	var ticker = time.NewTicker(plainFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.total(time.Since(start))
+		case <-p.stop:
+			p.total(time.Since(start))
+			return
+		}
+	}
+}
+
+func (p *IngestionProgress) total(elapsed time.Duration) {
+	fmt.Fprintf(p.plain, "%d/%d files, %d synsets written in %v\n", p.files.Value(), p.files.Total, p.synsets.Value(), elapsed.Round(time.Second))
+}
+
+func (p *IngestionProgress) FileStarted(file string) {
+	if p.writer == nil {
+		return
+	}
+
+	t := &progress.Tracker{Message: file}
+
+	p.mu.Lock()
+	p.trackers[file] = t
+	p.mu.Unlock()
+
+	p.writer.AppendTracker(t)
+}
+
+func (p *IngestionProgress) FileFinished(file string, synsets int, err error) {
+	p.files.Increment(1)
+
+	if p.writer == nil {
+		if err != nil {
+			fmt.Fprintf(p.plain, "%s: %v\n", file, err)
+		}
+		return
+	}
+
+	p.mu.Lock()
+	t := p.trackers[file]
+	delete(p.trackers, file)
+	p.mu.Unlock()
+
+	t.SetValue(int64(synsets))
+	if err != nil {
+		t.MarkAsErrored()
+	} else {
+		t.MarkAsDone()
+	}
+}
+
+func (p *IngestionProgress) BatchWritten(synsets int, err error) {
+	if err != nil {
+		p.synsets.IncrementWithError(int64(synsets))
+		return
+	}
+
+	p.synsets.Increment(int64(synsets))
+}
+
+// Stop - completes every tracker and waits for the final frame to render.
+func (p *IngestionProgress) Stop() {
+	if p.writer == nil {
+		close(p.stop)
+		<-p.done
+		return
+	}
+
+	p.files.MarkAsDone()
+	p.synsets.MarkAsDone()
+
+	for deadline := time.Now().Add(time.Second); p.writer.LengthActive() > 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	p.writer.Stop()
+	time.Sleep(100 * time.Millisecond)
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"io/fs"
 	"os"
 	"time"
 
@@ -38,10 +39,15 @@ type CommandState struct {
 	ExecutionStartTime time.Time
 	ExecutionExitLog   []any
 	Database           protocols.SqlBackend
+	Adapter            protocols.SQLAdapter
+	Files              fs.FS // the embedded dictionary files, loaded by the memory adapter
 }
 
 var cliDir = ".redic"
 
+// Exception lists of imported WordNet releases, within cliDir.
+var exceptionsDir = "exceptions"
+
 var defaultFlags = CommandFlags{
 	DatabaseName:    "r.sqlite",
 	DatabaseURL:     helpers.PointerTo(os.Getenv("DATABASE_URL")),
@@ -51,7 +57,7 @@ var defaultFlags = CommandFlags{
 		Default: "plain",
 	},
 	Engine: &FlagEnum{
-		Allowed: []string{"postgresql", "sqlite3" /*,"turso"*/},
+		Allowed: []string{"postgresql", "sqlite3", "sqlite", "memory" /*,"turso"*/},
 		Default: "sqlite3",
 	},
 	ServerAddr: "0.0.0.0:40301",
@@ -9,6 +9,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var lexiconLanguage string
+var verbatim bool
+
 var DefineCmd = &cobra.Command{
 	Use:     "define",
 	Aliases: []string{"d"},
@@ -24,7 +27,7 @@ var DefineCmd = &cobra.Command{
 		defer cancel()
 
 		// TODO: Review arguments to function call
-		definitions, err := app.DictionaryController.GetDefinition(ctx, types.GetWordDefinitionsInput{Word: args[0], PartOfSpeech: "*"})
+		definitions, err := app.DictionaryController.GetDefinition(ctx, types.GetWordDefinitionsInput{Word: args[0], PartOfSpeech: "*", Language: lexiconLanguage, Verbatim: verbatim})
 		if err != nil {
 			panic(err)
 		}
@@ -32,3 +35,8 @@ var DefineCmd = &cobra.Command{
 		state.Writer.Print(definitions)
 	},
 }
+
+func init() {
+	DefineCmd.Flags().BoolVar(&verbatim, "verbatim", verbatim, "only define the word as given, not the base forms it may be an inflection of (i.e geese → goose)")
+	DefineCmd.Flags().StringVar(&lexiconLanguage, "language", lexiconLanguage, "BCP-47 language of the word, i.e pt (every language by default)")
+}
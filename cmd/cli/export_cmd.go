@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/oleoneto/redic/app"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+	"github.com/oleoneto/redic/app/pkg/parsers"
+	"github.com/oleoneto/redic/cmd/cli/core"
+	"github.com/spf13/cobra"
+)
+
+var exportFormat = core.FlagEnum{Allowed: helpers.Map(parsers.GlossaryFormats, func(_ int, f parsers.Format) string { return string(f) })}
+
+var ExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Export the dictionary as a CSV, TSV or JSONL glossary (to stdout by default).",
+	Long: `Export the dictionary as a CSV, TSV or JSONL glossary (to stdout by default).
+
+Every synset is written with its members, definition, examples, interlingual index,
+lexicographer file and outgoing relations (i.e hypernym:02083346-n;similar:00003356-a,
+on its first row), so that importing the glossary again gives back the same dictionary.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		state.BeforeHook(cmd, args)
+		state.ConnectDatabase(cmd, args)
+	},
+	PersistentPostRun: state.AfterHook,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 15*time.Minute)
+		defer cancel()
+
+		columns, err := parsers.ParseColumns(glossaryColumns)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		var out io.Writer = os.Stdout
+		format := parsers.Format(exportFormat.Default)
+
+		if len(args) == 1 {
+			f, err := os.Create(args[0])
+			if err != nil {
+				log.Fatalln(err)
+			}
+			defer f.Close()
+
+			out = f
+
+			if detected, ok := parsers.DetectFormat(args[0]); ok && format == "" {
+				format = detected
+			}
+		}
+
+		if format == "" {
+			format = parsers.CSV
+		}
+
+		writer, err := parsers.NewGlossaryWriter(out, format, columns)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		if err := app.DictionaryController.ExportGlossary(ctx, writer.Write); err != nil {
+			log.Fatalln(err)
+		}
+
+		if err := writer.Flush(); err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
+
+func init() {
+	ExportCmd.Flags().Var(&exportFormat, "format", "glossary format (detected from the file extension, csv by default)")
+	ExportCmd.Flags().StringVar(&glossaryColumns, "columns", glossaryColumns, "glossary column mapping, i.e word=Term,definition=Meaning,part_of_speech=POS")
+}
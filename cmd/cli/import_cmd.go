@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+
+	"github.com/oleoneto/redic/app"
+	"github.com/oleoneto/redic/app/domain/protocols"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/helpers"
+	"github.com/oleoneto/redic/app/pkg/ingestion"
+	"github.com/oleoneto/redic/app/pkg/parsers"
+	"github.com/oleoneto/redic/cmd/cli/core"
+	"github.com/spf13/cobra"
+)
+
+var ingestionWorkers int
+var ingestionBatchSize = 1000
+
+var importFormat = core.FlagEnum{Allowed: helpers.Map(parsers.Formats, func(_ int, f parsers.Format) string { return string(f) })}
+var glossaryColumns string
+var ingestionLanguage = types.DefaultLanguage
+var forceIngestion bool
+var deferIndexing bool // rebuilds the search index once at the end, for imports of a whole dictionary
+
+var ImportCmd = &cobra.Command{
+	Use:   "import <path>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Import dictionary files from a directory, a single file, or a .tar.gz/.zip archive.",
+	Long: `Import dictionary files from a directory, a single file, or a .tar.gz/.zip archive.
+
+WordNet database releases (data.noun, data.verb, ...) are imported under the namespace of
+their release (i.e wn30:00001740-n), next to Open English WordNet. Their synsets are linked
+to the interlingual index when the release's ILI mapping (i.e ili-map-pwn30.tab) sits next
+to the data files, and their exception lists (noun.exc, verb.exc, ...) are kept in ~/.redic
+to lemmatize later searches with.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		state.BeforeHook(cmd, args)
+		state.ConnectDatabase(cmd, args)
+	},
+	PersistentPostRun: state.AfterHook,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 15*time.Minute)
+		defer cancel()
+
+		source, err := parsers.OpenSource(args[0])
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer source.Close()
+
+		columns, err := parsers.ParseColumns(glossaryColumns)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		if ingestionLanguage, err = types.NormalizeLanguage(ingestionLanguage); err != nil {
+			log.Fatalln(err)
+		}
+
+		parser := parsers.NewAutoParser(source.Loader(), source.Opener()).
+			WithFormat(parsers.Format(importFormat.Default)).
+			WithColumns(columns)
+
+		files := parser.LoadFiles(ctx, ".")
+		if len(files) == 0 {
+			log.Fatalln("no dictionary files found in", args[0])
+		}
+
+		Ingest(ctx, parser, source.Opener(), ".", files)
+
+		for _, list := range parsers.ExceptionLists(source.Loader(), ".") {
+			f, err := source.Opener()(list.Name())
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			err = state.KeepExceptionList(list.Name(), f)
+			f.Close()
+
+			if err != nil {
+				log.Fatalln(err)
+			}
+		}
+	},
+}
+
+// Ingest - runs the given files through the ingestion pipeline and prints a report.
+// Files that have not changed since they were last ingested are skipped, unless forced.
+// Exits with a non-zero status if anything failed.
+func Ingest(ctx context.Context, parser protocols.FileParserProtocol, opener protocols.OpenerFunc, dir string, files []fs.DirEntry) {
+	bar := core.NewIngestionProgress(len(files))
+
+	pipeline := ingestion.NewPipeline(parser, &app.DictionaryController, ingestion.Options{
+		Workers:   ingestionWorkers,
+		BatchSize: ingestionBatchSize,
+		Progress:  bar,
+		Language:  ingestionLanguage,
+		Checksum:  parsers.Checksum(opener),
+		Force:     forceIngestion,
+
+		DeferIndexing: deferIndexing,
+	})
+
+	report, err := pipeline.Run(ctx, dir, files)
+	bar.Stop()
+
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	state.Writer.Print(report)
+
+	if report.Failed() {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	ImportCmd.Flags().IntVar(&ingestionWorkers, "workers", ingestionWorkers, "number of files parsed concurrently (defaults to the number of CPUs)")
+	ImportCmd.Flags().IntVar(&ingestionBatchSize, "batch-size", ingestionBatchSize, "number of synsets written per transaction")
+	ImportCmd.Flags().StringVar(&ingestionLanguage, "language", ingestionLanguage, "BCP-47 language of files that do not declare one (LMF lexicons do)")
+	ImportCmd.Flags().Var(&importFormat, "format", "format of every file (detected from file extensions by default)")
+	ImportCmd.Flags().BoolVar(&forceIngestion, "force", forceIngestion, "re-import every file, even those that have not changed since their last import")
+	ImportCmd.Flags().StringVar(&glossaryColumns, "columns", glossaryColumns, "glossary column mapping, i.e word=Term,definition=Meaning,part_of_speech=POS")
+}
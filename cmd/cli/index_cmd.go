@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/oleoneto/redic/app"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/spf13/cobra"
+)
+
+var IndexCmd = &cobra.Command{
+	Use:       "index [optimize|rebuild]",
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{string(types.OptimizeIndex), string(types.RebuildIndex)},
+	Short:     "Optimize the full-text search index, or rebuild it from the dictionary.",
+	Long: `Optimize the full-text search index, or rebuild it from the dictionary.
+
+The index is kept up to date as words are added, so neither is needed for new words
+to be searchable. Optimizing (the default) speeds up searches after large imports;
+rebuilding repairs an index that went out of step, i.e after editing the database by hand.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		state.BeforeHook(cmd, args)
+		state.ConnectDatabase(cmd, args)
+	},
+	PersistentPostRun: state.AfterHook,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 15*time.Minute)
+		defer cancel()
+
+		maintenance := types.OptimizeIndex
+		if len(args) == 1 {
+			maintenance = types.IndexMaintenance(args[0])
+		}
+
+		if err := app.DictionaryController.IndexWords(ctx, maintenance); err != nil {
+			log.Fatalln(err)
+		}
+	},
+}
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -12,8 +11,7 @@ import (
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/oleoneto/go-toolkit/files"
-	"github.com/oleoneto/redic/app"
-	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/migrations"
 	"github.com/oleoneto/redic/app/pkg/parsers"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -48,7 +46,7 @@ var InitCmd = &cobra.Command{
 		}
 
 		if resetTables || repopulateDatabase {
-			state.ConnectDatabase(cmd, args)
+			state.OpenDatabase(cmd, args)
 		}
 
 		CreateTables(ctx, cmd, args)
@@ -76,37 +74,28 @@ func CopyDatabase(_ context.Context, cmd *cobra.Command, args []string) {
 	}
 }
 
+// CreateTables - brings the schema up to date, dropping every table first when resetting.
 func CreateTables(_ context.Context, cmd *cobra.Command, args []string) {
-	if !resetTables {
+	if state.Database == nil {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
 	defer cancel()
 
-	createTables := func(ctx context.Context) error {
-		tx, terr := state.Database.BeginTx(ctx, nil)
-		if terr != nil {
-			return terr
-		}
-
-		f := filepath.Join("data", "redic.sql")
-		b, err := virtualFS.ReadFile(f)
-		if err != nil {
-			return err
-		}
-
-		if _, err := tx.ExecContext(ctx, string(b)); err != nil {
-			tx.Rollback()
-			return err
-		}
+	migrator, err := migrations.NewMigrator(state.Database, state.Adapter)
+	if err != nil {
+		log.Fatalln(err)
+	}
 
-		return tx.Commit()
+	if resetTables {
+		err = migrator.Reset(ctx)
+	} else {
+		err = migrator.Up(ctx)
 	}
 
-	if err := createTables(ctx); err != nil {
+	if err != nil {
 		log.Fatalln(err)
-		return
 	}
 }
 
@@ -118,10 +107,11 @@ func PopulateTables(_ context.Context, cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithTimeout(context.TODO(), 15*time.Minute)
 	defer cancel()
 
-	parser := parsers.DefaultParser(
-		virtualFS.ReadDir,
-		virtualFS.ReadFile,
-	)
+	opener := func(name string) (io.ReadCloser, error) { return virtualFS.Open(name) }
+	parser := parsers.NewAutoParser(virtualFS.ReadDir, opener)
+
+	/* Every synset of the dictionary is written, so the index is built once they all are */
+	deferIndexing = true
 
 	dictDirectory := filepath.Join("data", "english")
 	files := parser.LoadFiles(
@@ -129,38 +119,14 @@ func PopulateTables(_ context.Context, cmd *cobra.Command, args []string) {
 		dictDirectory,
 	)
 
-	if len(files) == 0 {
-		return
-	}
-
-	defer app.DictionaryController.IndexWords(ctx)
-
-	fmt.Printf("%d files to process\n", len(files))
-
-	ch := make(chan types.DictFile)
-	parser.ParseFiles(ctx, dictDirectory, files, func(pf *types.ParsedFile) error {
-		ch <- pf.Data
-		return nil
-	})
-
-	for _, i := range files {
-		select {
-		case file := <-ch:
-			fmt.Println("Processsing", i.Name())
-
-			for _, f := range file {
-				if err := app.DictionaryController.CreateWords(ctx, f.Words()); err != nil {
-					log.Fatalln(err)
-				}
-			}
-		case <-ctx.Done():
-			fmt.Printf("Done processing all %d files\n", len(files))
-		}
-	}
+	Ingest(ctx, parser, opener, dictDirectory, files)
 }
 
 func init() {
-	InitCmd.Flags().BoolVar(&resetTables, "reset-tables", resetTables, "")
+	InitCmd.Flags().BoolVar(&resetTables, "reset-tables", resetTables, "drop every table and migrate the schema from scratch")
 	InitCmd.Flags().BoolVar(&repopulateDatabase, "repopulate", repopulateDatabase, "")
 	InitCmd.Flags().BoolVar(&copyDefaultDatabase, "copy-db", copyDefaultDatabase, "")
+	InitCmd.Flags().IntVar(&ingestionWorkers, "workers", ingestionWorkers, "number of files parsed concurrently (defaults to the number of CPUs)")
+	InitCmd.Flags().BoolVar(&forceIngestion, "force", forceIngestion, "repopulate every file, even those that have not changed since they were last ingested")
+	InitCmd.Flags().IntVar(&ingestionBatchSize, "batch-size", ingestionBatchSize, "number of synsets written per transaction")
 }
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/oleoneto/redic/app/pkg/lint"
+	"github.com/oleoneto/redic/app/pkg/parsers"
+	"github.com/spf13/cobra"
+)
+
+var strictLint bool
+var lintReferences string
+
+var LintCmd = &cobra.Command{
+	Use:   "lint <path>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Validate dictionary files from a directory, a single file, or a .tar.gz/.zip archive.",
+	Long: `Validate dictionary files without importing them.
+
+Reports dangling relation targets, duplicate synsets, synsets without members or
+definitions, parts of speech that disagree with their file name, circular hypernym
+chains and self-referential definitions.
+
+Relation targets are looked up among the files being linted and, with --references,
+among those of another directory or archive (i.e the whole lexicon when only the files
+that changed are linted). When linting a single file without references, targets
+outside of it are reported as warnings.
+
+Exits with a non-zero status if any error (or, with --strict, any warning) is found.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 15*time.Minute)
+		defer cancel()
+
+		source, err := parsers.OpenSource(args[0])
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer source.Close()
+
+		columns, err := parsers.ParseColumns(glossaryColumns)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		parser := parsers.NewAutoParser(source.Loader(), source.Opener()).
+			WithFormat(parsers.Format(importFormat.Default)).
+			WithColumns(columns)
+
+		files := parser.LoadFiles(ctx, ".")
+		if len(files) == 0 {
+			log.Fatalln("no dictionary files found in", args[0])
+		}
+
+		linter := lint.NewLinter(parser).WithPartial(source.SingleFile())
+
+		if lintReferences != "" {
+			references, err := parsers.OpenSource(lintReferences)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			defer references.Close()
+
+			referenceParser := parsers.NewAutoParser(references.Loader(), references.Opener()).
+				WithFormat(parsers.Format(importFormat.Default)).
+				WithColumns(columns)
+
+			ids, err := lint.Synsets(ctx, referenceParser, ".", referenceParser.LoadFiles(ctx, "."))
+			if err != nil {
+				log.Fatalln(err)
+			}
+
+			linter.WithReferences(ids)
+		}
+
+		report, err := linter.Run(ctx, ".", files)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		state.Writer.Print(report)
+
+		if report.Failed(strictLint) {
+			source.Close()
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	LintCmd.Flags().BoolVar(&strictLint, "strict", strictLint, "exit with a non-zero status on warnings too")
+	LintCmd.Flags().StringVar(&lintReferences, "references", lintReferences, "directory, file or archive whose synsets relations may also point at, i.e the rest of the lexicon")
+	LintCmd.Flags().Var(&importFormat, "format", "format of every file (detected from file extensions by default)")
+	LintCmd.Flags().StringVar(&glossaryColumns, "columns", glossaryColumns, "glossary column mapping, i.e word=Term,definition=Meaning,part_of_speech=POS")
+}
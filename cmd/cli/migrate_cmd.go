@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/oleoneto/redic/app/pkg/migrations"
+	"github.com/oleoneto/redic/cmd/cli/core"
+	"github.com/spf13/cobra"
+)
+
+var MigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply, revert and list database schema migrations.",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		state.BeforeHook(cmd, args)
+		state.OpenDatabase(cmd, args)
+	},
+	PersistentPostRun: state.AfterHook,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Args:  cobra.NoArgs,
+	Short: "Apply every pending migration.",
+	Run: func(cmd *cobra.Command, args []string) {
+		migrate(func(ctx context.Context, m *migrations.Migrator) error { return m.Up(ctx) })
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [steps]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Revert the last applied migration, or the last [steps] of them.",
+	Run: func(cmd *cobra.Command, args []string) {
+		var steps = 1
+
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				log.Fatalln("invalid number of steps:", args[0])
+			}
+
+			steps = n
+		}
+
+		migrate(func(ctx context.Context, m *migrations.Migrator) error { return m.Down(ctx, steps) })
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Args:  cobra.NoArgs,
+	Short: "List every migration and whether it has been applied.",
+	Run: func(cmd *cobra.Command, args []string) {
+		migrate(func(context.Context, *migrations.Migrator) error { return nil })
+	},
+}
+
+var migrateToCmd = &cobra.Command{
+	Use:   "to <version|name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Apply or revert migrations until the given one is the latest applied.",
+	Run: func(cmd *cobra.Command, args []string) {
+		target, err := core.ParseVersionArgs(args[0])
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		migrate(func(ctx context.Context, m *migrations.Migrator) error {
+			migration, err := m.Find(target.Value)
+			if err != nil {
+				return err
+			}
+
+			return m.To(ctx, migration.Version)
+		})
+	},
+}
+
+// migrate - runs an operation against the database and prints the resulting status.
+func migrate(operation func(context.Context, *migrations.Migrator) error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Minute)
+	defer cancel()
+
+	migrator, err := migrations.NewMigrator(state.Database, state.Adapter)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := operation(ctx, migrator); err != nil {
+		log.Fatalln(err)
+	}
+
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	state.Writer.Print(status)
+}
+
+func init() {
+	MigrateCmd.AddCommand(migrateUpCmd)
+	MigrateCmd.AddCommand(migrateDownCmd)
+	MigrateCmd.AddCommand(migrateStatusCmd)
+	MigrateCmd.AddCommand(migrateToCmd)
+}
@@ -40,6 +40,11 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	RootCmd.AddCommand(InitCmd)
+	RootCmd.AddCommand(ImportCmd)
+	RootCmd.AddCommand(ExportCmd)
+	RootCmd.AddCommand(LintCmd)
+	RootCmd.AddCommand(MigrateCmd)
+	RootCmd.AddCommand(IndexCmd)
 	RootCmd.AddCommand(VersionCmd)
 	RootCmd.AddCommand(SearchCmd)
 	RootCmd.AddCommand(DefineCmd)
@@ -76,6 +81,7 @@ func initConfig() {
 
 func Execute(vfs embed.FS, buildHash string) error {
 	virtualFS = vfs
+	state.Files = vfs
 
 	// MARK: Set up global glags
 	RootCmd.PersistentFlags().BoolVar(&state.Flags.VerboseLogging, "verbose", state.Flags.VerboseLogging, "enable detailed logging")
@@ -2,19 +2,32 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/oleoneto/redic/app"
 	"github.com/oleoneto/redic/app/domain/types"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
+var targetLanguage string
+var searchCategory string
+var searchLimit int
+var searchCursor string
+
 var SearchCmd = &cobra.Command{
 	Use:     "search",
 	Aliases: []string{"s"},
 	Args:    cobra.ArbitraryArgs,
 	Short:   "Search for words matching a definition.",
+	Example: `  redic search small long-haired terrier
+  redic search '"sea lion" -walrus'
+  redic search 'NEAR(dog bark, 3) OR howl*'`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		state.BeforeHook(cmd, args)
 		state.ConnectDatabase(cmd, args)
@@ -24,14 +37,39 @@ var SearchCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.TODO(), 2*time.Second)
 		defer cancel()
 
-		fmt.Println("-o", state.Flags.OutputFormat)
+		words, err := app.DictionaryController.FindMatchingWords(ctx, types.GetDescribedWordsInput{
+			Tokens:         strings.Join(args, " "),
+			Language:       lexiconLanguage,
+			TargetLanguage: targetLanguage,
+			Category:       searchCategory,
+			Limit:          searchLimit,
+			Cursor:         searchCursor,
+		})
 
-		// TODO: Add arguments to function call
-		words, err := app.DictionaryController.FindMatchingWords(ctx, types.GetDescribedWordsInput{})
+		/* Malformed queries are shown with a caret under where they went wrong */
+		var invalid *types.QueryError
+		if errors.As(err, &invalid) {
+			column := utf8.RuneCountInString(invalid.Query[:invalid.Position])
+			fmt.Fprintf(os.Stderr, "%s\n%s^ %s\n", invalid.Query, strings.Repeat(" ", column), invalid.Message)
+			os.Exit(1)
+		}
 		if err != nil {
 			panic(err)
 		}
 
+		/* Matches stand out in color on terminals, unless told otherwise (https://no-color.org) */
+		if cmd.Flag("output").Value.String() == "plain" && term.IsTerminal(int(os.Stdout.Fd())) && os.Getenv("NO_COLOR") == "" {
+			words.Mark(types.ANSIMarkup)
+		}
+
 		state.Writer.Print(words)
 	},
 }
+
+func init() {
+	SearchCmd.Flags().StringVar(&lexiconLanguage, "language", lexiconLanguage, "BCP-47 language of the description, i.e pt (every language by default)")
+	SearchCmd.Flags().StringVar(&targetLanguage, "target-language", targetLanguage, "translate matches into words of this language through their interlingual index, i.e en")
+	SearchCmd.Flags().StringVar(&searchCategory, "category", searchCategory, "only match synsets of this lexicographer file, i.e noun.animal")
+	SearchCmd.Flags().IntVar(&searchLimit, "limit", types.DefaultLimit, fmt.Sprintf("how many matches to show, at most %d", types.MaxLimit))
+	SearchCmd.Flags().StringVar(&searchCursor, "cursor", searchCursor, "show the matches after this cursor, as given with the previous page")
+}
@@ -3,6 +3,9 @@ package adapters
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -27,6 +30,7 @@ func (ad *DictionaryControllerAdapter) GetWordDefinition(c *fiber.Ctx) error {
 	type queryParams struct {
 		PartOfSpeech types.PartOfSpeech `query:"part_of_speech"`
 		Verbatim     bool               `query:"verbatim"`
+		Language     string             `query:"language"`
 	}
 
 	var q queryParams
@@ -36,9 +40,13 @@ func (ad *DictionaryControllerAdapter) GetWordDefinition(c *fiber.Ctx) error {
 		Word:         c.Params("word"),
 		PartOfSpeech: q.PartOfSpeech,
 		Verbatim:     q.Verbatim,
+		Language:     q.Language,
 	}
 
 	res, err := ad.controller.GetDefinition(ctx, req)
+	if errors.Is(err, types.ErrInvalidLanguage) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 	if err != nil {
 		return err
 	}
@@ -46,33 +54,109 @@ func (ad *DictionaryControllerAdapter) GetWordDefinition(c *fiber.Ctx) error {
 	return c.JSON(res)
 }
 
-func (ad *DictionaryControllerAdapter) FindWords(c *fiber.Ctx) error {
+func (ad *DictionaryControllerAdapter) GetRelatedWords(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.UserContext(), 2*time.Second)
 	defer cancel()
 
 	type queryParams struct {
-		Query        string             `query:"q"`
 		PartOfSpeech types.PartOfSpeech `query:"part_of_speech"`
-		Cursor       string             `query:"cursor"`
+		Relation     types.RelationType `query:"relation"`
 	}
 
 	var q queryParams
 	c.QueryParser(&q)
 
-	req := types.GetDescribedWordsInput{
-		Tokens:       q.Query,
+	var req = types.GetRelatedWordsInput{
+		Word:         c.Params("word"),
 		PartOfSpeech: q.PartOfSpeech,
-		Cursor:       q.Cursor,
+		RelationType: q.Relation,
+	}
+
+	res, err := ad.controller.GetRelatedWords(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(res)
+}
+
+func (ad *DictionaryControllerAdapter) FindWords(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), 2*time.Second)
+	defer cancel()
+
+	type queryParams struct {
+		Query          string             `query:"q"`
+		PartOfSpeech   types.PartOfSpeech `query:"part_of_speech"`
+		Cursor         string             `query:"cursor"`
+		Limit          int                `query:"limit"`
+		Language       string             `query:"language"`
+		TargetLanguage string             `query:"target_language"`
+		Category       string             `query:"category"`
+		Markup         string             `query:"markup"`
+	}
+
+	var q queryParams
+	c.QueryParser(&q)
+
+	if q.Markup != "" && q.Markup != "html" {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("unknown markup %q, only html is supported", q.Markup))
+	}
+
+	req := types.GetDescribedWordsInput{
+		Tokens:         q.Query,
+		PartOfSpeech:   q.PartOfSpeech,
+		Cursor:         q.Cursor,
+		Limit:          q.Limit,
+		Language:       q.Language,
+		TargetLanguage: q.TargetLanguage,
+		Category:       q.Category,
 	}
 
 	res, err := ad.controller.FindMatchingWords(ctx, req)
+
+	/* Malformed queries point at where they went wrong */
+	var invalid *types.QueryError
+	if errors.As(err, &invalid) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": invalid})
+	}
+	if errors.Is(err, types.ErrInvalidPage) || errors.Is(err, types.ErrInvalidLanguage) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 	if err != nil {
 		return err
 	}
 
+	/* Pages link to the first one and, while there are more, the next (RFC 8288) */
+	links := []string{pageURL(c, ""), "first"}
+	if res.NextCursor != "" {
+		links = append(links, pageURL(c, res.NextCursor), "next")
+	}
+	c.Links(links...)
+
+	/* Highlights are byte offsets, unless asked to mark them up in the text */
+	if q.Markup == "html" {
+		res.Mark(types.HTMLMarkup)
+	}
+
 	return c.JSON(res)
 }
 
+// pageURL - the URL of the request, paged from the given cursor instead (the first page when empty).
+func pageURL(c *fiber.Ctx, cursor string) string {
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+	query.Del("cursor")
+
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	if len(query) == 0 {
+		return c.BaseURL() + c.Path()
+	}
+
+	return c.BaseURL() + c.Path() + "?" + query.Encode()
+}
+
 // GET dictionary/words/:word
 func (ad *DictionaryControllerAdapter) CreateWords(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.UserContext(), 2*time.Second)
@@ -0,0 +1,61 @@
+package adapters_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oleoneto/redic/app/controllers"
+	"github.com/oleoneto/redic/app/domain/types"
+	"github.com/oleoneto/redic/app/pkg/repositories/memory"
+	"github.com/oleoneto/redic/cmd/web/negotiators/json/adapters"
+)
+
+func Test_DictionaryControllerAdapter_Status(t *testing.T) {
+	dictionary := memory.NewDictionary()
+
+	err := dictionary.NewSynsets(context.Background(), []types.NewSynsetInput{{
+		Id:           "02084071-n",
+		PartOfSpeech: "n",
+		Definitions:  []string{"a member of the genus Canis that has been domesticated by man since prehistoric times"},
+		Members:      []string{"dog"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller := controllers.NewDictionaryController(dictionary, func(any) map[string][]string { return nil })
+	adapter := adapters.NewDictionaryControllerAdapter(&controller)
+
+	server := fiber.New()
+	server.Get("/words/:word", adapter.GetWordDefinition)
+	server.Get("/words", adapter.FindWords)
+
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{target: "/words?q=canis", want: fiber.StatusOK},
+		{target: "/words?q=canis&language=en", want: fiber.StatusOK},
+		{target: "/words?q=canis&language=klingon", want: fiber.StatusBadRequest},
+		{target: "/words?q=canis&target_language=klingon", want: fiber.StatusBadRequest},
+		{target: "/words?q=(canis", want: fiber.StatusBadRequest},
+		{target: "/words?q=canis&cursor=abc", want: fiber.StatusBadRequest},
+		{target: "/words/dog", want: fiber.StatusOK},
+		{target: "/words/dog?language=klingon", want: fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			res, err := server.Test(httptest.NewRequest(fiber.MethodGet, tt.target, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != tt.want {
+				t.Errorf("GET %s = %d, want %d", tt.target, res.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -10,10 +10,14 @@ import (
 func Router(router fiber.Router) {
 	var dictionaryAdapter = adapters.NewDictionaryControllerAdapter(&app.DictionaryController)
 
-	// i.e /words/alone?part_of_speech=n
+	// i.e /words/alone?part_of_speech=n&language=en
 	router.Get("/words/:word", dictionaryAdapter.GetWordDefinition).Name("get-word-definition")
 
+	// i.e /words/dog/related?relation=hypernym
+	router.Get("/words/:word/related", dictionaryAdapter.GetRelatedWords).Name("get-related-words")
+
 	// i.e /dictionary/words?q=present_location&part_of_speech=n
+	// i.e /dictionary/words?q=lugar_presente&language=pt&target_language=en
 	router.Get("/words", dictionaryAdapter.FindWords).Name("find-word")
 
 	// router.Post("/words", dictionaryAdapter.CreateWords).Name("create-words")
@@ -43,6 +43,7 @@ func CreateServer() *fiber.App {
 		DisableStartupMessage: true,
 		ReadTimeout:           5 * time.Second,
 		PassLocalsToViews:     true,
+		UnescapePath:          true, // i.e /words/c%C3%A3o → cão
 		Views:                 views,
 	})
 
@@ -53,15 +54,19 @@ func CreateServer() *fiber.App {
 
 	server.Use(favicon.New(favicon.Config{File: "public/r.ico", FileSystem: http.FS(public)}))
 
+	probe := func(c *fiber.Ctx) bool {
+		/* Dictionaries kept in memory have no database to lose */
+		if app.DatabaseEngine == nil {
+			return true
+		}
+
+		_, err := app.DatabaseEngine.ExecContext(context.TODO(), "SELECT 1")
+		return err == nil
+	}
+
 	server.Use(healthcheck.New(healthcheck.Config{
-		LivenessProbe: func(c *fiber.Ctx) bool {
-			_, err := app.DatabaseEngine.ExecContext(context.TODO(), "SELECT 1")
-			return err == nil
-		},
-		ReadinessProbe: func(c *fiber.Ctx) bool {
-			_, err := app.DatabaseEngine.ExecContext(context.TODO(), "SELECT 1")
-			return err == nil
-		},
+		LivenessProbe:  probe,
+		ReadinessProbe: probe,
 	}))
 
 	server.Use(middleware.RequestLoggerMiddleware)
@@ -0,0 +1,12 @@
+// Package data embeds the dictionary redic ships with, so that it can be searched
+// without any setup:
+//
+//	dictionary, err := memory.Load(ctx, data.English, "english")
+package data
+
+import "embed"
+
+// English - the English WordNet, one file per lexicographer file (i.e english/noun.animal.yaml).
+//
+//go:embed english
+var English embed.FS